@@ -0,0 +1,462 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/logger"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/parser"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services/extractor"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services/generator"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services/validator"
+)
+
+// exitUsage is returned for command-line mistakes, matching the flag
+// package's own exit code.
+const exitUsage = 2
+
+// defaultConfigPath is the project config read when present. Unlike a path
+// given with --config or SKILLGEN_CONFIG, it may be missing.
+const defaultConfigPath = "skillgen.yaml"
+
+// envPrefix namespaces the environment variable behind each flag:
+// --plugin-metadata is SKILLGEN_PLUGIN_METADATA.
+const envPrefix = "SKILLGEN_"
+
+// options are the flags every subcommand shares: where the docs, templates,
+// config, and generated output live.
+type options struct {
+	configPath          string
+	sourcePath          string
+	sourceRef           string
+	outputPath          string
+	marketplacePath     string
+	readmePath          string
+	templatesPath       string
+	pluginMetadataPath  string
+	releaseManifestPath string
+	cachePath           string
+	manifestPath        string
+	mkdocsConfigPath    string
+	snippetPaths        string
+	categories          string
+	docsURL             string
+	jobs                int
+	verbose             bool
+}
+
+// newFlagSet creates a subcommand's flag set with the shared options
+// registered on it.
+func newFlagSet(name, args string, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: skillgen %s %s\n\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	fs.StringVar(&opts.configPath, "config", defaultConfigPath, "Path to skillgen.yaml project config")
+	fs.StringVar(&opts.sourcePath, "source", "", "Path to AEL documentation source (required)")
+	fs.StringVar(&opts.sourceRef, "source-ref", "", "Read --source from its git repository at this commit, tag, or branch instead of the working tree")
+	fs.StringVar(&opts.outputPath, "output", "./plugins", "Path to output generated plugins")
+	fs.StringVar(&opts.marketplacePath, "marketplace", "./.claude-plugin/marketplace.json", "Path to marketplace.json")
+	fs.StringVar(&opts.readmePath, "readme", "./README.md", "Path to generated README.md")
+	fs.StringVar(&opts.templatesPath, "templates", "./templates", "Path to template directory")
+	fs.StringVar(&opts.pluginMetadataPath, "plugin-metadata", "./plugin-metadata.json", "Path to plugin metadata config")
+	fs.StringVar(&opts.releaseManifestPath, "release-manifest", "./.release-please-manifest.json", "Path to release-please manifest")
+	fs.StringVar(&opts.manifestPath, "manifest", "./generated.json", "Path to the generated-files manifest")
+	fs.StringVar(&opts.mkdocsConfigPath, "mkdocs-config", "", "Path to the docs site's mkdocs.yml; its nav orders, titles, and selects topics")
+	fs.StringVar(&opts.snippetPaths, "snippet-paths", "", "Comma-separated directories --8<-- snippet includes are looked up in, in order (default: the docs root)")
+	fs.StringVar(&opts.cachePath, "cache", ".skillgen-cache", "Build cache directory; empty disables the cache")
+	fs.StringVar(&opts.categories, "categories", "", "Comma-separated categories to generate (default: all)")
+	fs.StringVar(&opts.docsURL, "docs-url", extractor.DefaultBaseURL, "Base URL of the published docs site")
+	fs.IntVar(&opts.jobs, "jobs", runtime.NumCPU(), "Number of documents and categories to process in parallel")
+	fs.BoolVar(&opts.verbose, "verbose", false, "Enable verbose logging")
+
+	return fs
+}
+
+// parseFlags parses args, then fills in every flag not given on the command
+// line from its SKILLGEN_* environment variable or, failing that, from
+// skillgen.yaml. Precedence is flags, then environment, then the config
+// file, then flag defaults.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	fs.Parse(args)
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	configPath, required := fs.Lookup("config").Value.String(), explicit["config"]
+	if env, ok := os.LookupEnv(envName("config")); ok && !required {
+		configPath, required = env, true
+	}
+
+	osFS := filesystem.NewFileSystem()
+	var fileValues map[string]string
+	if required || osFS.Exists(configPath) {
+		cfg, err := filesystem.NewProjectConfigReader(osFS).ReadProjectConfig(configPath)
+		if err != nil {
+			return err
+		}
+		fileValues = configFlagValues(cfg, filepath.Dir(configPath))
+	}
+	if err := fs.Set("config", configPath); err != nil {
+		return err
+	}
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || explicit[f.Name] || f.Name == "config" {
+			return
+		}
+
+		source := envName(f.Name)
+		value, ok := os.LookupEnv(source)
+		if !ok {
+			source = configPath
+			value, ok = fileValues[f.Name]
+		}
+		if !ok {
+			return
+		}
+
+		if setErr := fs.Set(f.Name, value); setErr != nil {
+			err = fmt.Errorf("%s: invalid value %q for --%s: %w", source, value, f.Name, setErr)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if jobs, _ := strconv.Atoi(fs.Lookup("jobs").Value.String()); jobs < 1 {
+		return fmt.Errorf("--jobs must be at least 1, got %d", jobs)
+	}
+
+	return nil
+}
+
+// envName returns the environment variable that overrides a flag.
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// configFlagValues maps each setting in cfg to the flag it stands in for.
+// Relative paths are resolved against dir, the config file's directory, so
+// skillgen.yaml means the same thing wherever skillgen is run from.
+func configFlagValues(cfg *domain.ProjectConfig, dir string) map[string]string {
+	values := make(map[string]string)
+	setPath := func(name, path string) {
+		if path == "" {
+			return
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(dir, path)
+		}
+		values[name] = path
+	}
+
+	setPath("source", cfg.Paths.Source)
+	setPath("output", cfg.Paths.Output)
+	setPath("marketplace", cfg.Paths.Marketplace)
+	setPath("readme", cfg.Paths.Readme)
+	setPath("templates", cfg.Paths.Templates)
+	setPath("plugin-metadata", cfg.Paths.PluginMetadata)
+	setPath("release-manifest", cfg.Paths.ReleaseManifest)
+	setPath("cache", cfg.Paths.Cache)
+	setPath("manifest", cfg.Paths.Manifest)
+	setPath("mkdocs-config", cfg.Paths.MkDocsConfig)
+	if len(cfg.Paths.Snippets) > 0 {
+		paths := make([]string, len(cfg.Paths.Snippets))
+		for i, path := range cfg.Paths.Snippets {
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			paths[i] = path
+		}
+		values["snippet-paths"] = strings.Join(paths, ",")
+	}
+
+	if cfg.Jobs != 0 {
+		values["jobs"] = strconv.Itoa(cfg.Jobs)
+	}
+	if cfg.Verbose != nil {
+		values["verbose"] = strconv.FormatBool(*cfg.Verbose)
+	}
+	if len(cfg.Categories) > 0 {
+		values["categories"] = strings.Join(cfg.Categories, ",")
+	}
+	if cfg.BaseURLs.Docs != "" {
+		values["docs-url"] = cfg.BaseURLs.Docs
+	}
+	if cfg.Output.FailOn != "" {
+		values["fail-on"] = cfg.Output.FailOn
+	}
+
+	return values
+}
+
+// selectCategories returns the categories named in list, or every category
+// when list is empty.
+func selectCategories(list string, all domain.CategorySet) (domain.CategorySet, error) {
+	if list == "" {
+		return all, nil
+	}
+
+	var categories domain.CategorySet
+	for _, name := range strings.Split(list, ",") {
+		category, ok := all.Lookup(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("unknown category %q: plugin-metadata.json defines %v", name, all.Names())
+		}
+		categories = append(categories, category)
+	}
+	return categories, nil
+}
+
+// usageError reports a command-line mistake and returns exitUsage.
+func usageError(fs *flag.FlagSet, msg string) int {
+	fmt.Fprintln(os.Stderr, msg)
+	fs.Usage()
+	return exitUsage
+}
+
+// app is the adapter and service wiring shared by every subcommand. It only
+// reads: where generated output goes is up to each command.
+type app struct {
+	opts           options
+	logger         ports.Logger
+	fs             *filesystem.FileSystem
+	source         ports.FileSystem
+	sourceCommit   string
+	nav            *domain.SiteNav
+	tree           *domain.DocsTree
+	categories     domain.CategorySet
+	documentReader *filesystem.DocumentReader
+	topicExtractor *extractor.TopicExtractor
+	hubBuilder     *extractor.HubBuilder
+	skillValidator *validator.SkillValidator
+	configReader   *filesystem.ConfigReader
+	pluginMetadata *domain.PluginMetadata
+
+	// cache is nil when caching is disabled. Entries are only valid for the
+	// same generator build and templates.
+	cache     ports.BuildCache
+	generator string
+	templates string
+}
+
+// newApp wires the adapters and reads plugin metadata, which every command
+// needs to build a hub.
+func newApp(opts options) (*app, error) {
+	// Initialize logger
+	logLevel := ports.LogLevelInfo
+	if opts.verbose {
+		logLevel = ports.LogLevelDebug
+	}
+	log := logger.NewLogger(logLevel)
+
+	// Initialize filesystem. Documents are read through the port, so
+	// discovery and parsing work on any ports.FileSystem; the docs root is
+	// made absolute so every document path is too.
+	fs := filesystem.NewFileSystem()
+	source := ports.FileSystem(fs)
+	if opts.sourcePath != "" {
+		sourcePath, err := filepath.Abs(opts.sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve --source: %w", err)
+		}
+		opts.sourcePath = sourcePath
+	}
+
+	// With --source-ref the docs come from git's object store instead, and
+	// an archive --source is read in place; either way at the same paths,
+	// so nothing downstream needs to know.
+	var sourceCommit string
+	switch {
+	case opts.sourceRef != "" && filesystem.IsArchive(opts.sourcePath):
+		return nil, fmt.Errorf("--source-ref needs --source to be a directory in a git repository, not an archive")
+
+	case opts.sourceRef != "":
+		tree, commit, err := filesystem.ReadGitTree(opts.sourcePath, opts.sourceRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read docs at --source-ref: %w", err)
+		}
+		log.Info("reading docs from git", "ref", opts.sourceRef, "commit", commit)
+		source, sourceCommit = tree, commit
+
+	case filesystem.IsArchive(opts.sourcePath):
+		tree, err := filesystem.OpenArchive(opts.sourcePath)
+		if err != nil {
+			return nil, err
+		}
+		log.Info("reading docs from archive", "archive", opts.sourcePath)
+		source = tree
+	}
+
+	// Plugin metadata is the source of truth for each hub's curated
+	// description and tags, and for the categories themselves.
+	configReader := filesystem.NewConfigReader(fs)
+	pluginMetadata, err := configReader.ReadPluginMetadata(opts.pluginMetadataPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plugin metadata: %w", err)
+	}
+	categories, err := selectCategories(opts.categories, pluginMetadata.Categories())
+	if err != nil {
+		return nil, err
+	}
+
+	// The docs site's nav, when given, curates which pages become topics
+	// and in what order. mkdocs.yml sits beside the docs, not in them, so
+	// it is always read from disk.
+	var nav *domain.SiteNav
+	if opts.mkdocsConfigPath != "" {
+		nav, err = filesystem.NewSiteNavReader(fs).ReadSiteNav(opts.mkdocsConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read --mkdocs-config: %w", err)
+		}
+	}
+
+	// Links between pages are checked against every file under the docs
+	// root, which may reach well outside the categories being generated.
+	var tree *domain.DocsTree
+	if opts.sourcePath != "" {
+		tree, err = filesystem.ReadDocsTree(source, opts.sourcePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list --source: %w", err)
+		}
+	}
+
+	// Snippet includes are read through the docs source, so --source-ref
+	// and archives apply to them too; a directory outside the docs root is
+	// only reachable from a working tree.
+	snippetPaths := []string{opts.sourcePath}
+	if opts.snippetPaths != "" {
+		snippetPaths = nil
+		for _, path := range strings.Split(opts.snippetPaths, ",") {
+			abs, err := filepath.Abs(strings.TrimSpace(path))
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve --snippet-paths: %w", err)
+			}
+			snippetPaths = append(snippetPaths, abs)
+		}
+	}
+
+	// Initialize parsers
+	frontmatterParser := parser.NewFrontmatterParser()
+	markdownParser := parser.NewMarkdownParser()
+	sectionParser := parser.NewSectionParser()
+	contentExtractor := parser.NewContentExtractor()
+	admonitionConverter := parser.NewAdmonitionConverter()
+	tabConverter := parser.NewTabConverter()
+	markupNormalizer := parser.NewMarkupNormalizer()
+	linkRewriter := parser.NewLinkRewriter()
+
+	// Initialize services
+	topicExtractor := extractor.NewTopicExtractor(opts.docsURL, opts.sourcePath, categories)
+	hubBuilder := extractor.NewHubBuilder(topicExtractor, admonitionConverter, tabConverter, markupNormalizer, linkRewriter, opts.docsURL, opts.sourcePath, sourceCommit, nav, tree, source)
+
+	// Initialize document reader
+	snippetResolver := filesystem.NewSnippetResolver(source, snippetPaths)
+	documentReader := filesystem.NewDocumentReader(source, frontmatterParser, markdownParser, sectionParser, contentExtractor, snippetResolver, categories)
+
+	// The build cache keys on the templates, so it can't be used without
+	// them; commands that need templates report their absence themselves.
+	var (
+		cache     ports.BuildCache
+		templates string
+	)
+	if opts.cachePath != "" {
+		templates, err = services.HashTemplates(fs, opts.templatesPath)
+		if err != nil {
+			log.Debug("build cache disabled", "error", err)
+		} else {
+			cache = filesystem.NewBuildCache(fs, opts.cachePath)
+		}
+	}
+
+	return &app{
+		opts:           opts,
+		logger:         log,
+		fs:             fs,
+		source:         source,
+		sourceCommit:   sourceCommit,
+		nav:            nav,
+		tree:           tree,
+		categories:     categories,
+		documentReader: documentReader,
+		topicExtractor: topicExtractor,
+		hubBuilder:     hubBuilder,
+		skillValidator: validator.NewSkillValidator(categories),
+		configReader:   configReader,
+		pluginMetadata: pluginMetadata,
+		cache:          cache,
+		generator:      generatorFingerprint(),
+		templates:      templates,
+	}, nil
+}
+
+// renderer loads the templates. Only commands that render output need them,
+// so they are not loaded up front.
+func (a *app) renderer() (*generator.TemplateRenderer, error) {
+	renderer, err := generator.NewTemplateRenderer(a.opts.templatesPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load templates: %w", err)
+	}
+	return renderer, nil
+}
+
+// validateMetadata cross-checks plugin metadata against the categories and
+// release versions, logging and recording each finding. versions may be
+// nil if the release manifest could not be read.
+func (a *app) validateMetadata(report *services.RunReport, versions map[string]string) {
+	findings := validator.NewMetadataValidator(a.opts.pluginMetadataPath, a.opts.releaseManifestPath).
+		Validate(a.pluginMetadata, versions, a.categories.Names())
+	for _, f := range findings {
+		logFinding(a.logger, "metadata validation", f)
+	}
+	report.AddValidation(findings)
+}
+
+// validateNav reports each page the docs site's nav links to that is
+// missing from the docs, if a nav was given.
+func (a *app) validateNav(report *services.RunReport) {
+	if a.nav == nil {
+		return
+	}
+	exists := func(page string) bool {
+		return a.source.Exists(filepath.Join(a.opts.sourcePath, filepath.FromSlash(page)))
+	}
+	findings := validator.NewNavValidator(a.opts.mkdocsConfigPath).Validate(a.nav, exists)
+	for _, f := range findings {
+		logFinding(a.logger, "nav validation", f)
+	}
+	report.AddValidation(findings)
+}
+
+// logFinding logs a validation finding at its own severity, with the file,
+// line, and column it points at when known.
+func logFinding(log ports.Logger, msg string, f ports.ValidationError, keysAndValues ...interface{}) {
+	if f.File != "" {
+		keysAndValues = append(keysAndValues, "file", f.File)
+	}
+	if f.Line > 0 {
+		keysAndValues = append(keysAndValues, "line", f.Line)
+	}
+	if f.Column > 0 {
+		keysAndValues = append(keysAndValues, "column", f.Column)
+	}
+	keysAndValues = append(keysAndValues, "issue", f.Message)
+	if f.Severity == ports.SeverityError {
+		log.Error(msg, keysAndValues...)
+		return
+	}
+	log.Warn(msg, keysAndValues...)
+}
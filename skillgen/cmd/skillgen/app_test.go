@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseFlags_Precedence(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "skillgen.yaml")
+	if err := os.WriteFile(config, []byte("base_urls:\n  docs: https://config.example\njobs: 3\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		env      map[string]string
+		wantURL  string
+		wantJobs int
+	}{
+		{
+			name:     "flag over env and config",
+			args:     []string{"--docs-url", "https://flag.example", "--jobs", "5"},
+			env:      map[string]string{"SKILLGEN_DOCS_URL": "https://env.example", "SKILLGEN_JOBS": "4"},
+			wantURL:  "https://flag.example",
+			wantJobs: 5,
+		},
+		{
+			name:     "env over config",
+			env:      map[string]string{"SKILLGEN_DOCS_URL": "https://env.example"},
+			wantURL:  "https://env.example",
+			wantJobs: 3,
+		},
+		{
+			name:     "config over defaults",
+			wantURL:  "https://config.example",
+			wantJobs: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"SKILLGEN_DOCS_URL", "SKILLGEN_JOBS"} {
+				t.Setenv(name, "")
+				os.Unsetenv(name)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			var opts options
+			fs := newFlagSet("test", "[flags]", &opts)
+			if err := parseFlags(fs, append([]string{"--config", config}, tt.args...)); err != nil {
+				t.Fatalf("parseFlags: %v", err)
+			}
+			if opts.docsURL != tt.wantURL || opts.jobs != tt.wantJobs {
+				t.Errorf("docs-url %q, jobs %d; want %q, %d", opts.docsURL, opts.jobs, tt.wantURL, tt.wantJobs)
+			}
+		})
+	}
+}
+
+func TestParseFlags_DefaultsWithoutConfig(t *testing.T) {
+	t.Setenv("SKILLGEN_CONFIG", "")
+	os.Unsetenv("SKILLGEN_CONFIG")
+	t.Chdir(t.TempDir())
+
+	var opts options
+	fs := newFlagSet("test", "[flags]", &opts)
+	if err := parseFlags(fs, nil); err != nil {
+		t.Fatalf("parseFlags: %v", err)
+	}
+	if opts.outputPath != "./plugins" || opts.cachePath != ".skillgen-cache" {
+		t.Errorf("output %q, cache %q; want the flag defaults", opts.outputPath, opts.cachePath)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
+)
+
+// writeArchives writes the staged output to archives instead of to disk
+// and returns their paths. If name contains {plugin}, each generated
+// plugin's directory becomes its own archive, rooted at the plugin so it
+// unpacks as an installable plugin. Otherwise one archive holds the whole
+// marketplace, at the paths generated.json lists.
+func (a *app) writeArchives(files map[string][]byte, name string) ([]string, error) {
+	if !strings.Contains(name, "{plugin}") {
+		return []string{name}, writeArchive(name, filepath.Dir(a.opts.manifestPath), files, true)
+	}
+
+	var written []string
+	for _, category := range a.categories {
+		path := strings.ReplaceAll(name, "{plugin}", category.Name)
+		if err := writeArchive(path, filepath.Join(a.opts.outputPath, category.Name), files, false); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// writeArchive writes every file under root to the archive at path, named
+// relative to root. A file outside root is an error if all is set, and is
+// left out otherwise.
+func writeArchive(path, root string, files map[string][]byte, all bool) error {
+	archive, err := filesystem.NewArchiveWriter(path)
+	if err != nil {
+		return err
+	}
+
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return err
+	}
+	for file, content := range files {
+		absFile, err := filepath.Abs(file)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(absRoot, absFile)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			if all {
+				return fmt.Errorf("%s is outside %s, the archive's root", file, root)
+			}
+			continue
+		}
+		if err := archive.WriteFile(rel, content, 0644); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
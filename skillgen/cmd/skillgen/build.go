@@ -0,0 +1,482 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/logger"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services"
+)
+
+// categoryBuild is one category's trip through the pipeline: its discovered
+// files, the documents parsed from them, and the validated hub built from
+// those.
+type categoryBuild struct {
+	category domain.Category
+	files    []string
+	docs     []*domain.Document
+	hub      *domain.Skill
+	err      error
+
+	// cached is the category's build cache entry from the previous run, if
+	// any; hashes holds each file's content hash, by index into files, and
+	// snippets the content hash of each snippet file it includes; and key
+	// hashes all of the category's inputs, empty if any is unknown.
+	// fromCache is set when hub was reused rather than built.
+	cached    *domain.CategoryCache
+	hashes    []string
+	snippets  []map[string]string
+	key       string
+	fromCache bool
+
+	// Every unit of work logs and reports into its own output, so units can
+	// run in parallel and still be replayed in the order a serial run would
+	// have produced them: discovery, then each document, then the build.
+	discovered work
+	parsed     []work
+	built      work
+}
+
+// work holds back one unit's log entries and failures until replay.
+type work struct {
+	log    *logger.Recorder
+	report *services.RunReport
+}
+
+func newWork() work {
+	return work{log: logger.NewRecorder(), report: services.NewRunReport()}
+}
+
+func (w work) replay(a *app, report *services.RunReport) {
+	w.log.Replay(a.logger)
+	report.Merge(w.report)
+}
+
+// buildCategories discovers, parses, builds, and validates the hub for each
+// category, running up to --jobs units of work at once. Each stage fans out
+// over every category (or, for parsing, every document of every category)
+// before the next begins, so no worker ever waits on another. Results,
+// logs, and failures come back in category and file order, whatever the
+// scheduling.
+func (a *app) buildCategories(categories domain.CategorySet, report *services.RunReport) []*categoryBuild {
+	builds := make([]*categoryBuild, len(categories))
+	for i, category := range categories {
+		builds[i] = &categoryBuild{category: category, discovered: newWork(), built: newWork()}
+	}
+
+	services.RunBounded(a.opts.jobs, len(builds), func(i int) {
+		a.discover(builds[i])
+	})
+
+	type docRef struct {
+		build *categoryBuild
+		index int
+	}
+	var refs []docRef
+	for _, b := range builds {
+		b.docs = make([]*domain.Document, len(b.files))
+		b.hashes = make([]string, len(b.files))
+		b.snippets = make([]map[string]string, len(b.files))
+		b.parsed = make([]work, len(b.files))
+		for j := range b.files {
+			b.parsed[j] = newWork()
+			refs = append(refs, docRef{build: b, index: j})
+		}
+	}
+	services.RunBounded(a.opts.jobs, len(refs), func(i int) {
+		ref := refs[i]
+		ref.build.docs[ref.index] = a.parse(ref.build, ref.index, ref.build.parsed[ref.index])
+	})
+
+	services.RunBounded(a.opts.jobs, len(builds), func(i int) {
+		a.build(builds[i])
+	})
+
+	for _, b := range builds {
+		b.discovered.replay(a, report)
+		for _, w := range b.parsed {
+			w.replay(a, report)
+		}
+		b.built.replay(a, report)
+	}
+
+	return builds
+}
+
+// discover lists a category's markdown pages.
+func (a *app) discover(b *categoryBuild) {
+	w := b.discovered
+	w.log.Info("discovering documents", "category", b.category.Name)
+
+	files, err := a.documentReader.ListDocuments(a.opts.sourcePath, domain.CategorySet{b.category})
+	if err != nil {
+		w.log.Error("failed to discover documents", "category", b.category.Name, "error", err)
+		w.report.HubError(services.FailureDiscovery, filepath.Join(a.opts.sourcePath, filepath.FromSlash(b.category.SourceDir)), err)
+		b.err = err
+		return
+	}
+
+	b.files, err = a.selectPages(b, files)
+	if err != nil {
+		b.err = err
+		return
+	}
+
+	if a.cache != nil {
+		cached, err := a.cache.Load(b.category.Name)
+		if err != nil {
+			w.log.Warn("ignoring unreadable build cache", "category", b.category.Name, "error", err)
+		}
+		b.cached = cached
+	}
+}
+
+// selectPages drops the pages the category's include and exclude rules
+// leave out, and, given a nav, the pages it does not list, as the site
+// itself does. The category's root index.md is always kept, since every
+// hub needs it for its overview. An ignore file that can't be read or
+// parsed is a discovery failure, and the category is left unbuilt rather
+// than built from pages it may exclude.
+func (a *app) selectPages(b *categoryBuild, files []string) ([]string, error) {
+	w := b.discovered
+	cfg := a.pluginMetadata.Plugins[b.category.Name]
+	rules := domain.PageRules{Include: cfg.Include, Exclude: cfg.Exclude}
+
+	sourceDir := filepath.Join(a.opts.sourcePath, filepath.FromSlash(b.category.SourceDir))
+	if ignorePath := filepath.Join(sourceDir, domain.IgnoreFileName); a.source.Exists(ignorePath) {
+		content, err := a.source.ReadFile(ignorePath)
+		if err != nil {
+			w.log.Error("failed to read ignore file", "path", ignorePath, "error", err)
+			w.report.HubError(services.FailureDiscovery, ignorePath, err)
+			return nil, err
+		}
+		patterns := domain.ParseIgnoreFile(string(content))
+		if err := (domain.PageRules{Exclude: patterns}).Validate(); err != nil {
+			w.log.Error("invalid ignore file", "path", ignorePath, "error", err)
+			w.report.HubError(services.FailureDiscovery, ignorePath, err)
+			return nil, err
+		}
+		rules.Exclude = append(rules.Exclude[:len(rules.Exclude):len(rules.Exclude)], patterns...)
+	}
+
+	var kept []string
+	for _, file := range files {
+		rel, err := filepath.Rel(sourceDir, file)
+		page := filepath.ToSlash(rel)
+		switch {
+		case err != nil || strings.EqualFold(page, "index.md"):
+		case !rules.Allows(page):
+			w.log.Debug("skipping excluded page", "path", file)
+			continue
+		case a.nav != nil && !a.listed(b.category.SourceDir+"/"+page):
+			w.log.Debug("skipping page not in the nav", "path", file)
+			continue
+		}
+		kept = append(kept, file)
+	}
+	return kept, nil
+}
+
+// docsRel returns path's slash-separated path relative to the docs root.
+func (a *app) docsRel(path string) string {
+	rel, err := filepath.Rel(a.opts.sourcePath, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// listed reports whether the nav lists the page at path, relative to the
+// docs root.
+func (a *app) listed(path string) bool {
+	_, _, ok := a.nav.Lookup(path)
+	return ok
+}
+
+// parse reads and parses the document at b.files[i], reusing the previous
+// run's parse if the content and generator are unchanged. It returns nil
+// for a document that failed, is a blog post, or excludes itself, none of
+// which is a topic.
+func (a *app) parse(b *categoryBuild, i int, w work) *domain.Document {
+	filePath := b.files[i]
+
+	doc := a.cachedDocument(b, i)
+	if doc != nil {
+		w.log.Debug("reusing cached parse", "path", filePath)
+	} else {
+		var err error
+		doc, err = a.documentReader.ReadDocument(filePath)
+		if err != nil {
+			w.log.Error("failed to read document", "path", filePath, "error", err)
+			w.report.Error(services.FailureParse, filePath, err)
+			return nil
+		}
+		b.snippets[i] = a.hashSnippets(doc)
+	}
+
+	if doc.Frontmatter.IsBlogPost() {
+		w.log.Debug("skipping blog post", "path", filePath)
+		return nil
+	}
+
+	directives, err := doc.Frontmatter.Directives()
+	if err != nil {
+		w.log.Error("invalid skillgen frontmatter", "path", filePath, "error", err)
+		w.report.Error(services.FailureParse, filePath, err)
+		return nil
+	}
+	if directives.Exclude {
+		// The category root can't opt out: without it there is no hub.
+		if strings.EqualFold(a.docsRel(filePath), b.category.SourceDir+"/index.md") {
+			finding := ports.ValidationError{
+				Severity: ports.SeverityWarning,
+				Message:  "skillgen exclude is ignored on a category's root index.md",
+				File:     filePath,
+			}
+			logFinding(w.log, "frontmatter validation", finding)
+			w.report.AddValidation([]ports.ValidationError{finding})
+		} else {
+			w.log.Debug("skipping page excluded by its frontmatter", "path", filePath)
+			return nil
+		}
+	}
+
+	for _, snippet := range doc.Snippets {
+		if snippet.Problem == "" {
+			continue
+		}
+		finding := ports.ValidationError{
+			Severity: ports.SeverityWarning,
+			Message:  snippet.Problem,
+			File:     filePath,
+			Line:     snippet.Line,
+		}
+		logFinding(w.log, "snippet validation", finding)
+		w.report.AddValidation([]ports.ValidationError{finding})
+	}
+
+	return doc
+}
+
+// hashSnippets hashes each snippet file doc includes, by path. A file that
+// can't be read is left out, which keeps the category's hub from being
+// cached.
+func (a *app) hashSnippets(doc *domain.Document) map[string]string {
+	hashes := make(map[string]string, len(doc.Snippets))
+	for _, snippet := range doc.Snippets {
+		if snippet.Path == "" {
+			continue
+		}
+		if content, err := a.source.ReadFile(snippet.Path); err == nil {
+			hashes[snippet.Path] = services.HashContent(content)
+		}
+	}
+	return hashes
+}
+
+// hashAssets hashes each local file docs link to, by path. A file that
+// can't be read is left out: the hub leaves it out too, and the docs tree
+// in the cache key notices if it turns up.
+func (a *app) hashAssets(docs []*domain.Document) map[string]string {
+	hashes := make(map[string]string)
+	for _, doc := range docs {
+		for _, asset := range doc.Assets {
+			path := asset.Path
+			if _, ok := hashes[path]; ok || a.source.IsDir(path) {
+				continue
+			}
+			if content, err := a.source.ReadFile(path); err == nil {
+				hashes[path] = services.HashContent(content)
+			}
+		}
+	}
+	return hashes
+}
+
+// cachedDocument hashes the document at b.files[i] and returns its cached
+// parse if one matches, or nil. The hash is recorded even with the cache
+// disabled, since the generated-files manifest lists it too.
+func (a *app) cachedDocument(b *categoryBuild, i int) *domain.Document {
+	content, err := a.source.ReadFile(b.files[i])
+	if err != nil {
+		// ReadDocument reports the failure.
+		return nil
+	}
+	b.hashes[i] = services.HashContent(content)
+
+	if a.cache == nil || b.cached == nil || b.cached.Generator != a.generator {
+		return nil
+	}
+	// A parse records the paths it was read from, so it is only reused
+	// when the docs root has not moved.
+	entry, ok := b.cached.Documents[a.docsRel(b.files[i])]
+	if !ok || entry.Hash != b.hashes[i] || entry.Document == nil || entry.Document.Path != b.files[i] {
+		return nil
+	}
+
+	// The parse is stale if a snippet it included changed, and is always
+	// redone if one was missing, in case it has since turned up.
+	for _, snippet := range entry.Document.Snippets {
+		if snippet.Problem != "" {
+			return nil
+		}
+	}
+	snippets := a.hashSnippets(entry.Document)
+	if len(snippets) != len(entry.Snippets) {
+		return nil
+	}
+	for path, hash := range entry.Snippets {
+		if snippets[path] != hash {
+			return nil
+		}
+	}
+	b.snippets[i] = snippets
+	return entry.Document
+}
+
+// build assembles and validates a category's hub skill from its parsed
+// documents. Validation findings are advisory: a hub that fails validation
+// is still kept, but is surfaced so it can be fixed at the source document.
+func (a *app) build(b *categoryBuild) {
+	if b.err != nil {
+		return
+	}
+
+	var docs []*domain.Document
+	for _, doc := range b.docs {
+		if doc != nil {
+			docs = append(docs, doc)
+		}
+	}
+	b.docs = docs
+
+	w := b.built
+	pluginCfg := a.pluginMetadata.Plugins[b.category.Name]
+	b.key = a.cacheKey(b, pluginCfg)
+
+	var hub *domain.Skill
+	if b.key != "" && b.cached != nil && b.cached.Key == b.key && b.cached.Hub != nil {
+		w.log.Debug("reusing cached hub skill", "category", b.category.Name)
+		hub, b.fromCache = b.cached.Hub, true
+	} else {
+		var err error
+		hub, err = a.hubBuilder.Build(b.category.Name, docs, pluginCfg)
+		if err != nil {
+			w.log.Error("failed to build hub skill", "category", b.category.Name, "error", err)
+			w.report.HubError(services.FailureValidation, filepath.Join(a.opts.sourcePath, filepath.FromSlash(b.category.SourceDir)), err)
+			b.err = err
+			return
+		}
+	}
+
+	findings := a.skillValidator.Validate(hub)
+	for _, f := range findings {
+		logFinding(w.log, "skill validation", f, "name", hub.Metadata.Name)
+	}
+	w.report.AddValidation(findings)
+
+	b.hub = hub
+}
+
+// cacheKey hashes every input of the category's hub, or returns "" if the
+// cache is disabled or a document could not be hashed.
+func (a *app) cacheKey(b *categoryBuild, pluginCfg domain.PluginConfig) string {
+	if a.cache == nil {
+		return ""
+	}
+
+	documents := make(map[string]string, len(b.files))
+	for i, path := range b.files {
+		if b.hashes[i] == "" {
+			return ""
+		}
+		documents[path] = b.hashes[i]
+	}
+
+	hashed := make(map[string]string)
+	for _, hashes := range b.snippets {
+		for path, hash := range hashes {
+			hashed[path] = hash
+		}
+	}
+	snippets := make(map[string]string)
+	for _, doc := range b.docs {
+		for _, snippet := range doc.Snippets {
+			hash, ok := hashed[snippet.Path]
+			if !ok {
+				return ""
+			}
+			snippets[snippet.Path] = hash
+		}
+	}
+
+	key, err := services.CategoryInputs{
+		Generator: a.generator,
+		Templates: a.templates,
+		DocsURL:   a.opts.docsURL,
+		Category:  b.category,
+		Plugin:    pluginCfg,
+		Documents: documents,
+		Snippets:  snippets,
+		Assets:    a.hashAssets(b.docs),
+		Files:     a.tree.Paths(),
+
+		Nav: a.nav.Under(b.category.SourceDir),
+	}.Key()
+	if err != nil {
+		return ""
+	}
+	return key
+}
+
+// storeCache records what b parsed and built, and the files now under
+// skillsDir, for the next run.
+func (a *app) storeCache(b *categoryBuild, skillsDir string) error {
+	if a.cache == nil || b.key == "" {
+		return nil
+	}
+
+	outputs, err := services.HashTree(a.fs, skillsDir)
+	if err != nil {
+		return err
+	}
+
+	hashes := make(map[string]string, len(b.files))
+	snippets := make(map[string]map[string]string, len(b.files))
+	for i, path := range b.files {
+		hashes[path] = b.hashes[i]
+		snippets[path] = b.snippets[i]
+	}
+	documents := make(map[string]domain.CachedDocument, len(b.docs))
+	for _, doc := range b.docs {
+		documents[a.docsRel(doc.Path)] = domain.CachedDocument{Hash: hashes[doc.Path], Snippets: snippets[doc.Path], Document: doc}
+	}
+
+	return a.cache.Store(b.category.Name, &domain.CategoryCache{
+		Generator: a.generator,
+		Key:       b.key,
+		Documents: documents,
+		Hub:       b.hub,
+		Outputs:   outputs,
+	})
+}
+
+// buildHubs builds and validates one hub skill per category, returning the
+// hubs that built and the number of documents indexed.
+func (a *app) buildHubs(report *services.RunReport) ([]*domain.Skill, int) {
+	var (
+		hubs   []*domain.Skill
+		topics int
+	)
+
+	for _, b := range a.buildCategories(a.categories, report) {
+		topics += len(b.docs)
+		if b.hub != nil {
+			hubs = append(hubs, b.hub)
+		}
+	}
+
+	return hubs, topics
+}
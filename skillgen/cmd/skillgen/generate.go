@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services"
+)
+
+// runGenerate implements "skillgen generate": regenerate every hub skill,
+// the marketplace files, and README.md.
+func runGenerate(args []string) int {
+	return generate("generate", args, false)
+}
+
+// runDiff implements "skillgen diff": the same run as generate --check.
+func runDiff(args []string) int {
+	return generate("diff", args, true)
+}
+
+func generate(name string, args []string, check bool) int {
+	var (
+		opts          options
+		failOn        string
+		outputArchive string
+	)
+	fs := newFlagSet(name, "[flags]", &opts)
+	if !check {
+		fs.BoolVar(&check, "check", false, "Generate in memory and fail if the committed output differs")
+		fs.StringVar(&outputArchive, "output-archive", "", "Write the output to this .zip or .tar.gz instead of to disk; {plugin} in the name writes one archive per plugin")
+	}
+	fs.StringVar(&failOn, "fail-on", "never", "Exit non-zero on: never, error, or warning")
+	if err := parseFlags(fs, args); err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	if opts.sourcePath == "" {
+		return usageError(fs, "--source is required: pass it, set SKILLGEN_SOURCE, or set paths.source in skillgen.yaml")
+	}
+	failPolicy, err := services.ParseFailPolicy(failOn)
+	if err != nil {
+		return usageError(fs, fmt.Sprintf("--fail-on: %v", err))
+	}
+	if outputArchive != "" && check {
+		return usageError(fs, "--output-archive and --check cannot be combined")
+	}
+	if outputArchive != "" && !filesystem.IsArchive(outputArchive) {
+		return usageError(fs, "--output-archive must end in .zip, .tar.gz, or .tgz")
+	}
+
+	a, err := newApp(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	templateRenderer, err := a.renderer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	a.logger.Info("AEL Claude Skills Generator")
+	a.logger.Info("source", opts.sourcePath)
+	a.logger.Info("output", opts.outputPath)
+	a.logger.Info("plugin-metadata", opts.pluginMetadataPath)
+	a.logger.Info("release-manifest", opts.releaseManifestPath)
+
+	// Sources are always read from disk, but generated output is staged in
+	// memory. In check mode it is then compared against what is committed;
+	// otherwise it is swapped into place only once all of it has rendered,
+	// so a failure partway through never leaves a missing or half-written
+	// hub behind.
+	staged := filesystem.NewMemoryFileSystem()
+
+	// Initialize writers
+	skillWriter := filesystem.NewSkillWriter(staged, templateRenderer)
+	marketplaceWriter := filesystem.NewMarketplaceWriter(staged)
+
+	report := services.NewRunReport()
+
+	versions, versionsErr := a.configReader.ReadReleaseManifest(opts.releaseManifestPath)
+	a.validateMetadata(report, versions)
+	a.validateNav(report)
+
+	// Build one hub skill per category.
+	var (
+		builtHubs []*domain.Skill
+		builds    = a.buildCategories(a.categories, report)
+		written   []*categoryBuild
+		ownedDirs []string
+		kept      = make(map[string]string)
+		topics    int
+		unchanged int
+	)
+	for _, b := range builds {
+		topics += len(b.docs)
+		hub := b.hub
+		if hub == nil {
+			continue
+		}
+
+		// A hub rebuilt from unchanged inputs whose output is still exactly
+		// as the last run left it has nothing to write, and its skills/
+		// directory is left alone. Check mode always writes, since it
+		// compares every hub, and so does archive output, which holds them
+		// all.
+		category := hub.Metadata.Category
+		skillsDir := filepath.Join(opts.outputPath, category, "skills")
+		if !check && outputArchive == "" && b.fromCache && services.TreeUnchanged(a.fs, skillsDir, b.cached.Outputs) {
+			a.logger.Info("hub skill unchanged", "category", category)
+			builtHubs = append(builtHubs, hub)
+			unchanged++
+			for path, hash := range b.cached.Outputs {
+				kept[path] = hash
+			}
+			continue
+		}
+
+		if err := skillWriter.WriteSkill(hub, opts.outputPath); err != nil {
+			a.logger.Error("failed to write hub skill", "category", category, "error", err)
+			report.Error(services.FailureWrite, filepath.Join(opts.outputPath, category), err)
+			continue
+		}
+
+		a.logger.Info("generated hub skill", "category", category, "groups", len(hub.Groups))
+		builtHubs = append(builtHubs, hub)
+		written = append(written, b)
+
+		// Each hub's skills/ directory is rewritten wholesale, so anything
+		// there that wasn't just generated is stale.
+		ownedDirs = append(ownedDirs, skillsDir)
+	}
+
+	// A library file whose docs didn't change keeps the docs commit it
+	// already records, so a new commit alone rewrites nothing.
+	for path, content := range staged.Files() {
+		if kept := services.KeepSourceCommit(a.fs, path, content); !bytes.Equal(kept, content) {
+			if err := staged.WriteFile(path, kept, 0644); err != nil {
+				report.Error(services.FailureWrite, path, err)
+			}
+		}
+	}
+
+	// Generate marketplace files
+	a.logger.Info("generating marketplace files")
+	marketplaceGen := services.NewMarketplaceGenerator(a.configReader, marketplaceWriter, a.logger)
+	err = marketplaceGen.Generate(opts.pluginMetadataPath, opts.releaseManifestPath, opts.outputPath, opts.marketplacePath)
+	if err != nil {
+		a.logger.Error("failed to generate marketplace files", "error", err)
+		report.Error(services.FailureMarketplace, opts.marketplacePath, err)
+	} else {
+		a.logger.Info("marketplace files generated successfully")
+	}
+
+	// Generate README.md from the same hubs and metadata just used above,
+	// so it can never drift from what was actually generated.
+	a.logger.Info("generating README.md")
+	if versionsErr != nil {
+		a.logger.Error("failed to read release manifest for README", "error", versionsErr)
+		report.Error(services.FailureReadme, opts.releaseManifestPath, versionsErr)
+	} else {
+		readmeGen := services.NewReadmeGenerator(templateRenderer, staged, a.logger)
+		if err := readmeGen.Generate(builtHubs, a.pluginMetadata, versions, opts.readmePath); err != nil {
+			a.logger.Error("failed to generate README.md", "error", err)
+			report.Error(services.FailureReadme, opts.readmePath, err)
+		} else {
+			a.logger.Info("README.md generated successfully")
+		}
+	}
+
+	// generated.json lists everything above, so it is written last.
+	if err := a.writeManifest(staged, builds, kept); err != nil {
+		a.logger.Error("failed to generate generated.json", "error", err)
+		report.Error(services.FailureWrite, opts.manifestPath, err)
+	}
+
+	var (
+		drifts   []domain.FileDrift
+		changed  int
+		archives []string
+	)
+	switch {
+	case check:
+		drifts, err = services.NewDriftChecker(a.fs).Check(staged.Files(), ownedDirs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to compare generated output: %v\n", err)
+			return 1
+		}
+		for _, d := range drifts {
+			a.logger.Error("committed output is stale", "path", d.Path, "drift", d.Kind)
+			fmt.Print(d.Diff)
+		}
+
+	case report.OutputExitCode() != services.ExitOK:
+		// A hub failed to build or something failed to render, so the
+		// output is incomplete; the previous output is better than that. A
+		// document that failed to parse or validate is only left out of
+		// its hub, and doesn't stop the rest from being written.
+		a.logger.Error("output left unchanged because a hub failed to build or it failed to render or write")
+
+	case outputArchive != "":
+		// Nothing lands on disk but the archives, so the build cache,
+		// which records what is on disk, is left as it was.
+		archives, err = a.writeArchives(staged.Files(), outputArchive)
+		if err != nil {
+			a.logger.Error("failed to write output archive", "error", err)
+			report.Error(services.FailureWrite, outputArchive, err)
+			break
+		}
+		for _, archive := range archives {
+			a.logger.Info("output archived", "archive", archive)
+		}
+
+	default:
+		changed, err = filesystem.NewOutputCommitter().Commit(staged.Files(), ownedDirs)
+		if err != nil {
+			a.logger.Error("failed to write output", "error", err)
+			report.Error(services.FailureWrite, opts.outputPath, err)
+			break
+		}
+		a.logger.Info("output written", "changed", changed)
+
+		// Only output that actually landed on disk is worth remembering.
+		for _, b := range written {
+			category := b.hub.Metadata.Category
+			if err := a.storeCache(b, filepath.Join(opts.outputPath, category, "skills")); err != nil {
+				a.logger.Warn("failed to update build cache", "category", category, "error", err)
+			}
+		}
+	}
+
+	// Summary
+	fmt.Println("\n=== Generation Summary ===")
+	fmt.Printf("Categories:     %d\n", len(a.categories))
+	fmt.Printf("Topics indexed: %d\n", topics)
+	fmt.Printf("Hub skills:     %d\n", len(builtHubs))
+	if a.cache != nil {
+		fmt.Printf("Unchanged:      %d\n", unchanged)
+	}
+	fmt.Printf("Warnings:       %d\n", report.Warnings())
+	fmt.Printf("Errors:         %d\n", report.Errors())
+	fmt.Printf("Output:         %s\n", opts.outputPath)
+	if a.sourceCommit != "" {
+		fmt.Printf("Source commit:  %s\n", a.sourceCommit)
+	}
+	switch {
+	case check:
+		fmt.Printf("Drifted files:  %d\n", len(drifts))
+	case outputArchive != "":
+		fmt.Printf("Archives:       %d\n", len(archives))
+	default:
+		fmt.Printf("Files changed:  %d\n", changed)
+	}
+
+	report.WriteSummary(os.Stdout)
+
+	if errors := report.Errors(); errors > 0 {
+		a.logger.Info("completed with errors", "count", errors)
+	} else {
+		a.logger.Info("completed successfully")
+	}
+
+	// With the default --fail-on=never, errors are logged for visibility
+	// but don't fail the build, unless output could not be rendered or
+	// written. A failure the policy does count takes precedence over drift:
+	// output from a broken run isn't worth diffing.
+	if code := report.ExitCode(failPolicy); code != services.ExitOK {
+		return code
+	}
+	if len(drifts) > 0 {
+		return services.ExitDrift
+	}
+	return services.ExitOK
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services"
+)
+
+// runInspect implements "skillgen inspect <doc>": build the document's whole
+// hub exactly as generate would, then show where that one document landed
+// in it — its role, group, topic entry, library path, and reference body.
+func runInspect(args []string) int {
+	var opts options
+	fs := newFlagSet("inspect", "[flags] <doc>", &opts)
+	if err := parseFlags(fs, args); err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	if opts.sourcePath == "" {
+		return usageError(fs, "--source is required: pass it, set SKILLGEN_SOURCE, or set paths.source in skillgen.yaml")
+	}
+	if fs.NArg() != 1 {
+		return usageError(fs, "inspect takes exactly one document path")
+	}
+
+	docPath, err := filepath.Abs(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	a, err := newApp(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	category, _, ok := a.categories.Locate(a.docsRel(docPath))
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s is not under any category: %v\n", docPath, a.categories.Names())
+		return 1
+	}
+
+	report := services.NewRunReport()
+	build := a.buildCategories(domain.CategorySet{category}, report)[0]
+	hub, docs, err := build.hub, build.docs, build.err
+	if err != nil {
+		report.WriteSummary(os.Stderr)
+		fmt.Fprintf(os.Stderr, "failed to build the %s hub: %v\n", category.Name, err)
+		return 1
+	}
+
+	var doc *domain.Document
+	for _, d := range docs {
+		if d.Path == docPath {
+			doc = d
+		}
+	}
+	if doc == nil {
+		report.WriteSummary(os.Stderr)
+		fmt.Fprintf(os.Stderr, "%s was not indexed: it failed to parse, is a blog post, is not a .md page under a category, or is left out of the nav\n", docPath)
+		return 1
+	}
+
+	fmt.Printf("Document:     %s\n", doc.Path)
+	fmt.Printf("Category:     %s\n", category.Name)
+	fmt.Printf("Frontmatter:  title=%q description=%q\n", doc.Frontmatter.Title, doc.Frontmatter.Description)
+
+	role, group, body := locate(hub, doc.Path)
+	fmt.Printf("Role:         %s\n", role)
+	if group != nil {
+		fmt.Printf("Group:        %s\n", group.Title)
+	}
+
+	if role == "topic" {
+		topic, err := a.topicExtractor.Extract(doc)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		fmt.Printf("Topic:        %s\n", topic.Title)
+		fmt.Printf("Description:  %s\n", topic.Description)
+		fmt.Printf("URL:          %s\n", topic.URL)
+		fmt.Printf("LibraryPath:  %s\n", topic.LibraryPath)
+	}
+	for _, lf := range hub.LibraryFiles {
+		if lf.SourcePath == doc.Path {
+			fmt.Printf("Library file: library/%s\n", lf.RelPath)
+		}
+	}
+
+	fmt.Println("\n=== Reference body ===")
+	fmt.Println(body)
+
+	return services.ExitOK
+}
+
+// locate finds the part of hub built from the document at path: the
+// category root, a group's own doc, or a topic within a group. It returns
+// the role, the enclosing group (nil for the root), and the reference body.
+func locate(hub *domain.Skill, path string) (string, *domain.TopicGroup, string) {
+	if hub.Metadata.SourcePath == path {
+		return "category root (hub overview)", nil, hub.Metadata.ReferenceBody
+	}
+	for i := range hub.Groups {
+		group := &hub.Groups[i]
+		if group.SourcePath == path {
+			return "group root", group, group.ReferenceBody
+		}
+		for _, topic := range group.Topics {
+			if topic.SourcePath == path {
+				return "topic", group, topic.ReferenceBody
+			}
+		}
+	}
+	return "not placed in the hub", nil, ""
+}
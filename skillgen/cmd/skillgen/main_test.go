@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{name: "unknown command", args: []string{"frobnicate"}, wantCode: exitUsage, wantStderr: `unknown command "frobnicate"`},
+		{name: "help", args: []string{"help"}, wantCode: 0, wantStdout: "Usage: skillgen <command> [flags]"},
+		{name: "version", args: []string{"version"}, wantCode: 0, wantStdout: "skillgen version dev"},
+		{name: "version flag", args: []string{"generate", "--version"}, wantCode: 0, wantStdout: "skillgen version dev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(stdout.String(), tt.wantStdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout.String(), tt.wantStdout)
+			}
+			if !strings.Contains(stderr.String(), tt.wantStderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+
+	var stderr bytes.Buffer
+	run([]string{"frobnicate"}, &bytes.Buffer{}, &stderr)
+	for _, c := range commands {
+		if !strings.Contains(stderr.String(), c.name) {
+			t.Errorf("usage = %q, want it to list %q", stderr.String(), c.name)
+		}
+	}
+}
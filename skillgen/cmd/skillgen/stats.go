@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services"
+)
+
+// runStats implements "skillgen stats": build every hub and report its size
+// as it would be written, without writing anything. Like validate, it fails
+// on errors by default, since sizes of hubs that failed to build are not
+// the sizes generate would write.
+func runStats(args []string) int {
+	var (
+		opts   options
+		failOn string
+	)
+	fs := newFlagSet("stats", "[flags]", &opts)
+	fs.StringVar(&failOn, "fail-on", "error", "Exit non-zero on: never, error, or warning")
+	if err := parseFlags(fs, args); err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	if opts.sourcePath == "" {
+		return usageError(fs, "--source is required: pass it, set SKILLGEN_SOURCE, or set paths.source in skillgen.yaml")
+	}
+	failPolicy, err := services.ParseFailPolicy(failOn)
+	if err != nil {
+		return usageError(fs, fmt.Sprintf("--fail-on: %v", err))
+	}
+
+	a, err := newApp(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	renderer, err := a.renderer()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	report := services.NewRunReport()
+	hubs, _ := a.buildHubs(report)
+
+	collector := services.NewStatsCollector(renderer)
+	var all []domain.HubStats
+	for _, hub := range hubs {
+		stats, err := collector.Collect(hub)
+		if err != nil {
+			a.logger.Error("failed to collect stats", "category", hub.Metadata.Category, "error", err)
+			report.Error(services.FailureWrite, hub.Metadata.SourcePath, err)
+			continue
+		}
+		all = append(all, stats)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "Hub\tDocs\tGroups\tTopics\tSKILL.md words\tSKILL.md bytes\treference.md words\treference.md bytes\tlibrary/ words\tlibrary/ bytes\t")
+
+	var total domain.HubStats
+	total.Category = "total"
+	for _, s := range all {
+		writeStatsRow(w, s)
+		total.Documents += s.Documents
+		total.Groups += s.Groups
+		total.Topics += s.Topics
+		total.SkillWords += s.SkillWords
+		total.SkillBytes += s.SkillBytes
+		total.ReferenceWords += s.ReferenceWords
+		total.ReferenceBytes += s.ReferenceBytes
+		total.LibraryWords += s.LibraryWords
+		total.LibraryBytes += s.LibraryBytes
+	}
+	writeStatsRow(w, total)
+	w.Flush()
+
+	report.WriteSummary(os.Stdout)
+
+	return report.ExitCode(failPolicy)
+}
+
+func writeStatsRow(w *tabwriter.Writer, s domain.HubStats) {
+	fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t\n",
+		s.Category, s.Documents, s.Groups, s.Topics,
+		s.SkillWords, s.SkillBytes,
+		s.ReferenceWords, s.ReferenceBytes,
+		s.LibraryWords, s.LibraryBytes)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services"
+)
+
+// runValidate implements "skillgen validate": every check generate runs,
+// with nothing rendered or written. It fails on errors by default, since
+// reporting problems is the whole point of running it.
+func runValidate(args []string) int {
+	var (
+		opts   options
+		failOn string
+	)
+	fs := newFlagSet("validate", "[flags]", &opts)
+	fs.StringVar(&failOn, "fail-on", "error", "Exit non-zero on: never, error, or warning")
+	if err := parseFlags(fs, args); err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	if opts.sourcePath == "" {
+		return usageError(fs, "--source is required: pass it, set SKILLGEN_SOURCE, or set paths.source in skillgen.yaml")
+	}
+	failPolicy, err := services.ParseFailPolicy(failOn)
+	if err != nil {
+		return usageError(fs, fmt.Sprintf("--fail-on: %v", err))
+	}
+
+	a, err := newApp(opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	report := services.NewRunReport()
+
+	versions, err := a.configReader.ReadReleaseManifest(opts.releaseManifestPath)
+	if err != nil {
+		a.logger.Error("failed to read release manifest", "error", err)
+		report.Error(services.FailureValidation, opts.releaseManifestPath, err)
+	}
+	a.validateMetadata(report, versions)
+	a.validateNav(report)
+
+	hubs, topics := a.buildHubs(report)
+
+	fmt.Println("\n=== Validation Summary ===")
+	fmt.Printf("Categories:     %d\n", len(a.categories))
+	fmt.Printf("Topics indexed: %d\n", topics)
+	fmt.Printf("Hub skills:     %d\n", len(hubs))
+	fmt.Printf("Warnings:       %d\n", report.Warnings())
+	fmt.Printf("Errors:         %d\n", report.Errors())
+
+	report.WriteSummary(os.Stdout)
+
+	return report.ExitCode(failPolicy)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/services"
+)
+
+// verifyLabels names each kind of problem verify reports.
+var verifyLabels = map[domain.DriftKind]string{
+	domain.DriftModified:   "hand-edited",
+	domain.DriftMissing:    "missing",
+	domain.DriftUnexpected: "unowned",
+}
+
+// runVerify implements "skillgen verify": check the committed output
+// against generated.json. It needs no docs checkout, so it is cheap enough
+// for a pre-commit hook.
+func runVerify(args []string) int {
+	var opts options
+	fs := newFlagSet("verify", "[flags]", &opts)
+	if err := parseFlags(fs, args); err != nil {
+		return usageError(fs, err.Error())
+	}
+
+	disk := filesystem.NewFileSystem()
+	manifest, err := filesystem.NewGeneratedManifestStore(disk).Read(opts.manifestPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	drifts, err := services.NewManifestVerifier(disk).Verify(manifest, opts.manifestPath, opts.outputPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to verify generated output: %v\n", err)
+		return 1
+	}
+
+	counts := make(map[domain.DriftKind]int)
+	for _, d := range drifts {
+		fmt.Printf("%-12s %s\n", verifyLabels[d.Kind], d.Path)
+		counts[d.Kind]++
+	}
+
+	fmt.Println("\n=== Verification Summary ===")
+	fmt.Printf("Files listed:   %d\n", len(manifest.Files))
+	fmt.Printf("Hand-edited:    %d\n", counts[domain.DriftModified])
+	fmt.Printf("Unowned:        %d\n", counts[domain.DriftUnexpected])
+	fmt.Printf("Missing:        %d\n", counts[domain.DriftMissing])
+
+	if len(drifts) > 0 {
+		fmt.Println("\nRegenerate with 'skillgen generate' instead of editing generated files.")
+		return services.ExitDrift
+	}
+	return services.ExitOK
+}
+
+// writeManifest writes generated.json to out, listing every file staged in
+// out plus the files of each hub left unchanged on disk, which were not
+// staged. Each hub's SKILL.md, reference.md, and library/ files record the
+// document they came from. A hub this run did not build, such as one left
+// out by --categories, is still on disk as the last run wrote it, so its
+// entries are carried over from the previous generated.json.
+func (a *app) writeManifest(out *filesystem.MemoryFileSystem, builds []*categoryBuild, unchanged map[string]string) error {
+	outputs := make(map[string]string)
+	for path, content := range out.Files() {
+		outputs[path] = services.HashContent(content)
+	}
+	for path, hash := range unchanged {
+		outputs[path] = hash
+	}
+
+	sources := make(map[string]string)
+	sourceHashes := make(map[string]string)
+	a.carryManifest(builds, outputs, sources, sourceHashes)
+	for _, b := range builds {
+		for i, path := range b.files {
+			sourceHashes[path] = b.hashes[i]
+		}
+
+		hub := b.hub
+		if hub == nil {
+			continue
+		}
+		skillDir := filepath.Join(a.opts.outputPath, hub.Metadata.Category, "skills", hub.Metadata.Name)
+		sources[filepath.Join(skillDir, "SKILL.md")] = hub.Metadata.SourcePath
+		sources[filepath.Join(skillDir, "reference.md")] = hub.Metadata.SourcePath
+		for _, lf := range hub.LibraryFiles {
+			sources[filepath.Join(skillDir, "library", filepath.FromSlash(lf.RelPath))] = lf.SourcePath
+		}
+		for _, asset := range hub.Assets {
+			sources[filepath.Join(skillDir, "library", filepath.FromSlash(asset.RelPath))] = asset.SourcePath
+		}
+	}
+
+	manifest, err := services.NewGeneratedManifest(a.opts.manifestPath, a.opts.sourcePath, outputs, sources, sourceHashes)
+	if err != nil {
+		return err
+	}
+	manifest.SourceCommit = a.sourceCommit
+
+	return filesystem.NewGeneratedManifestStore(out).Write(manifest, a.opts.manifestPath)
+}
+
+// carryManifest adds to outputs, sources, and sourceHashes the previous
+// generated.json's entries for the skills/ directories of hubs not built in
+// builds. Without a readable previous manifest there is nothing to carry.
+func (a *app) carryManifest(builds []*categoryBuild, outputs, sources, sourceHashes map[string]string) {
+	if !a.fs.Exists(a.opts.manifestPath) {
+		return
+	}
+	previous, err := filesystem.NewGeneratedManifestStore(a.fs).Read(a.opts.manifestPath)
+	if err != nil {
+		a.logger.Warn("not carrying over entries of hubs left unbuilt", "error", err)
+		return
+	}
+
+	built := make(map[string]bool)
+	for _, b := range builds {
+		if b.hub != nil {
+			built[b.hub.Metadata.Category] = true
+		}
+	}
+
+	manifestDir := filepath.Dir(a.opts.manifestPath)
+	for _, file := range previous.Files {
+		path := filepath.Join(manifestDir, filepath.FromSlash(file.Path))
+		rel, err := filepath.Rel(a.opts.outputPath, path)
+		if err != nil {
+			continue
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) < 3 || parts[0] == ".." || parts[1] != "skills" || built[parts[0]] {
+			continue
+		}
+		if _, ok := outputs[path]; ok {
+			continue
+		}
+
+		outputs[path] = file.SHA256
+		if file.Source != "" {
+			source := filepath.Join(a.opts.sourcePath, filepath.FromSlash(file.Source))
+			sources[path] = source
+			sourceHashes[source] = file.SourceSHA256
+		}
+	}
+}
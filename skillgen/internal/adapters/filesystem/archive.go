@@ -0,0 +1,318 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// archiveModTime is the modification time of every archived file, so the
+// same output always produces a byte-identical archive.
+var archiveModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// errWriteOnly is returned for every read from an ArchiveWriter.
+var errWriteOnly = errors.New("write-only filesystem")
+
+// archiveFormat returns "zip" or "tar.gz" for a path with that extension,
+// or "" for anything else.
+func archiveFormat(path string) string {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz"
+	}
+	return ""
+}
+
+// IsArchive reports whether path names a .zip, .tar.gz, or .tgz archive.
+func IsArchive(path string) bool {
+	return archiveFormat(path) != ""
+}
+
+// ArchiveFileSystem implements ports.FileSystem, read-only, over the
+// contents of a .zip or .tar.gz archive, loaded into memory. The archive's
+// root appears as a directory at the archive's own path, so
+// docs.tar.gz holding patterns/index.md serves docs.tar.gz/patterns/index.md.
+type ArchiveFileSystem struct {
+	*MemoryFileSystem
+}
+
+// Ensure ArchiveFileSystem implements ports.FileSystem
+var _ ports.FileSystem = (*ArchiveFileSystem)(nil)
+
+// OpenArchive loads the archive at archivePath. Only regular files are loaded;
+// directories are implied by the files in them, and links are skipped.
+func OpenArchive(archivePath string) (*ArchiveFileSystem, error) {
+	root, err := filepath.Abs(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &ArchiveFileSystem{MemoryFileSystem: NewMemoryFileSystem()}
+	add := func(name string, content []byte) error {
+		clean := strings.TrimPrefix(path.Clean("/"+name), "/")
+		if clean == "" || !fs.ValidPath(clean) {
+			return fmt.Errorf("invalid entry name %q", name)
+		}
+		return tree.MemoryFileSystem.WriteFile(filepath.Join(root, filepath.FromSlash(clean)), content, 0644)
+	}
+
+	switch archiveFormat(archivePath) {
+	case "zip":
+		err = readZip(archivePath, add)
+	case "tar.gz":
+		err = readTarGz(archivePath, add)
+	default:
+		err = errors.New("unsupported archive format: use .zip, .tar.gz, or .tgz")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive %s: %w", archivePath, err)
+	}
+
+	return tree, nil
+}
+
+// readZip calls add for each regular file in the zip archive at path.
+func readZip(path string, add func(name string, content []byte) error) error {
+	r, err := zip.OpenReader(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	for _, f := range r.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if err := add(f.Name, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readTarGz calls add for each regular file in the gzipped tarball at path.
+func readTarGz(path string, add func(name string, content []byte) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		content, err := io.ReadAll(tr)
+		if err != nil {
+			return fmt.Errorf("%s: %w", header.Name, err)
+		}
+		if err := add(header.Name, content); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteFile always fails: an ArchiveFileSystem is read-only.
+func (a *ArchiveFileSystem) WriteFile(path string, data []byte, perm int) error {
+	return &fs.PathError{Op: "write", Path: path, Err: errReadOnly}
+}
+
+// MkdirAll always fails: an ArchiveFileSystem is read-only.
+func (a *ArchiveFileSystem) MkdirAll(path string, perm int) error {
+	return &fs.PathError{Op: "mkdir", Path: path, Err: errReadOnly}
+}
+
+// RemoveAll always fails: an ArchiveFileSystem is read-only.
+func (a *ArchiveFileSystem) RemoveAll(path string) error {
+	return &fs.PathError{Op: "remove", Path: path, Err: errReadOnly}
+}
+
+// ArchiveWriter implements ports.FileSystem, write-only, as a .zip or
+// .tar.gz archive. Written paths are entry names inside the archive. Nothing
+// reaches disk until Close, which writes every entry in path order with a
+// fixed modification time, so equal output makes an identical archive.
+type ArchiveWriter struct {
+	path    string
+	entries map[string][]byte
+}
+
+// Ensure ArchiveWriter implements ports.FileSystem
+var _ ports.FileSystem = (*ArchiveWriter)(nil)
+
+// NewArchiveWriter creates a writer for the archive at path, whose
+// extension picks the format.
+func NewArchiveWriter(path string) (*ArchiveWriter, error) {
+	if !IsArchive(path) {
+		return nil, fmt.Errorf("unsupported archive format for %s: use .zip, .tar.gz, or .tgz", path)
+	}
+	return &ArchiveWriter{path: path, entries: make(map[string][]byte)}, nil
+}
+
+// ReadFile always fails: an ArchiveWriter is write-only.
+func (w *ArchiveWriter) ReadFile(path string) ([]byte, error) {
+	return nil, &fs.PathError{Op: "read", Path: path, Err: errWriteOnly}
+}
+
+// WriteFile adds path to the archive, replacing any earlier entry for it.
+func (w *ArchiveWriter) WriteFile(path string, data []byte, perm int) error {
+	name := filepath.ToSlash(filepath.Clean(path))
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: path, Err: errors.New("archive entries must be relative paths inside the archive")}
+	}
+	w.entries[name] = append([]byte(nil), data...)
+	return nil
+}
+
+// MkdirAll does nothing: directories are implied by the entries in them.
+func (w *ArchiveWriter) MkdirAll(path string, perm int) error {
+	return nil
+}
+
+// Glob matches nothing: an ArchiveWriter is write-only.
+func (w *ArchiveWriter) Glob(pattern string) ([]string, error) {
+	return nil, nil
+}
+
+// Exists is always false: an ArchiveWriter is write-only.
+func (w *ArchiveWriter) Exists(path string) bool {
+	return false
+}
+
+// IsDir is always false: an ArchiveWriter is write-only.
+func (w *ArchiveWriter) IsDir(path string) bool {
+	return false
+}
+
+// RemoveAll drops path and any entries under it.
+func (w *ArchiveWriter) RemoveAll(path string) error {
+	name := filepath.ToSlash(filepath.Clean(path))
+	for entry := range w.entries {
+		if entry == name || strings.HasPrefix(entry, name+"/") {
+			delete(w.entries, entry)
+		}
+	}
+	return nil
+}
+
+// WalkDir always fails: an ArchiveWriter is write-only.
+func (w *ArchiveWriter) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return fn(root, nil, &fs.PathError{Op: "walk", Path: root, Err: errWriteOnly})
+}
+
+// Close writes the archive to disk, atomically.
+func (w *ArchiveWriter) Close() error {
+	names := make([]string, 0, len(w.entries))
+	for name := range w.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", w.path, err)
+	}
+	temp, err := os.CreateTemp(filepath.Dir(w.path), "."+filepath.Base(w.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", w.path, err)
+	}
+
+	if archiveFormat(w.path) == "zip" {
+		err = w.writeZip(temp, names)
+	} else {
+		err = w.writeTarGz(temp, names)
+	}
+	if err == nil {
+		err = temp.Chmod(0644)
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), w.path)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write archive %s: %w", w.path, err)
+	}
+
+	return nil
+}
+
+func (w *ArchiveWriter) writeZip(out io.Writer, names []string) error {
+	zw := zip.NewWriter(out)
+	for _, name := range names {
+		header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: archiveModTime}
+		header.SetMode(0644)
+		f, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := f.Write(w.entries[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (w *ArchiveWriter) writeTarGz(out io.Writer, names []string) error {
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	for _, name := range names {
+		content := w.entries[name]
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			ModTime:  archiveModTime,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
@@ -0,0 +1,89 @@
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestArchive_RoundTrip(t *testing.T) {
+	for _, name := range []string{"docs.zip", "docs.tar.gz"} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), name)
+			w, err := NewArchiveWriter(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for file, content := range discoveryTree {
+				if err := w.WriteFile(file, []byte(content), 0644); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			first, _ := os.ReadFile(path)
+
+			// Equal output must make an identical archive.
+			if err := w.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if second, _ := os.ReadFile(path); !bytes.Equal(first, second) {
+				t.Error("expected rewriting the same entries to produce identical bytes")
+			}
+
+			archive, err := OpenArchive(path)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			root, _ := filepath.Abs(path)
+			files, err := FindDocuments(archive, root, discoveryCategories)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var want []string
+			for _, file := range wantDocuments {
+				want = append(want, filepath.Join(root, filepath.FromSlash(file[len("/docs/"):])))
+			}
+			if !reflect.DeepEqual(files, want) {
+				t.Errorf("expected %v, got %v", want, files)
+			}
+
+			content, err := archive.ReadFile(filepath.Join(root, "patterns", "a", "notes.md"))
+			if err != nil || string(content) != "# Notes" {
+				t.Errorf("expected the archived content, got %q, %v", content, err)
+			}
+			if err := archive.WriteFile(filepath.Join(root, "new.md"), nil, 0644); !errors.Is(err, errReadOnly) {
+				t.Errorf("expected a read-only error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestArchiveWriter(t *testing.T) {
+	if _, err := NewArchiveWriter("docs.rar"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+
+	w, err := NewArchiveWriter(filepath.Join(t.TempDir(), "out.zip"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.WriteFile("../escape.md", nil, 0644); err == nil {
+		t.Error("expected an error for an entry outside the archive")
+	}
+	if _, err := w.ReadFile("a.md"); !errors.Is(err, errWriteOnly) {
+		t.Errorf("expected a write-only error, got %v", err)
+	}
+
+	w.WriteFile("plugin/a.md", []byte("a"), 0644)
+	w.WriteFile("plugin/b.md", []byte("b"), 0644)
+	w.WriteFile("other.md", []byte("c"), 0644)
+	w.RemoveAll("plugin")
+	if len(w.entries) != 1 || w.entries["other.md"] == nil {
+		t.Errorf("expected only other.md to remain, got %v", w.entries)
+	}
+}
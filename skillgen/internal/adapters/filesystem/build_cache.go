@@ -0,0 +1,61 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// BuildCache implements ports.BuildCache as one JSON file per category in a
+// cache directory. Each file is rewritten whole, so documents that left the
+// category drop out of the cache with it.
+type BuildCache struct {
+	fs  ports.FileSystem
+	dir string
+}
+
+// NewBuildCache creates a build cache stored under dir.
+func NewBuildCache(fs ports.FileSystem, dir string) *BuildCache {
+	return &BuildCache{fs: fs, dir: dir}
+}
+
+// Load returns the category's cache entry, or nil if there is none.
+func (c *BuildCache) Load(category string) (*domain.CategoryCache, error) {
+	path := c.path(category)
+	if !c.fs.Exists(path) {
+		return nil, nil
+	}
+
+	content, err := c.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read build cache %s: %w", path, err)
+	}
+
+	var entry domain.CategoryCache
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse build cache %s: %w", path, err)
+	}
+
+	return &entry, nil
+}
+
+// Store replaces the category's cache entry.
+func (c *BuildCache) Store(category string, entry *domain.CategoryCache) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal build cache for %s: %w", category, err)
+	}
+
+	if err := c.fs.WriteFile(c.path(category), content, 0644); err != nil {
+		return fmt.Errorf("failed to write build cache for %s: %w", category, err)
+	}
+
+	return nil
+}
+
+func (c *BuildCache) path(category string) string {
+	return filepath.Join(c.dir, category+".json")
+}
@@ -0,0 +1,56 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+func TestBuildCache_RoundTrip(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	cache := NewBuildCache(fs, ".skillgen-cache")
+
+	entry, err := cache.Load("patterns")
+	if err != nil || entry != nil {
+		t.Fatalf("expected no entry before the first store, got %v, %v", entry, err)
+	}
+
+	stored := &domain.CategoryCache{
+		Generator: "1.2.3",
+		Key:       "key",
+		Documents: map[string]domain.CachedDocument{
+			"/docs/patterns/index.md": {
+				Hash:     "hash",
+				Document: &domain.Document{Path: "/docs/patterns/index.md", Frontmatter: domain.Frontmatter{Title: "Patterns"}},
+			},
+		},
+		Hub:     &domain.Skill{Metadata: domain.SkillMetadata{Name: "patterns"}},
+		Outputs: map[string]string{"plugins/patterns/skills/patterns/SKILL.md": "sha"},
+	}
+	if err := cache.Store("patterns", stored); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !fs.Exists(".skillgen-cache/patterns.json") {
+		t.Fatal("expected the entry to be written to .skillgen-cache/patterns.json")
+	}
+
+	loaded, err := cache.Load("patterns")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loaded.Key != "key" || loaded.Hub.Metadata.Name != "patterns" {
+		t.Errorf("unexpected entry: %+v", loaded)
+	}
+	if doc := loaded.Documents["/docs/patterns/index.md"].Document; doc == nil || doc.Frontmatter.Title != "Patterns" {
+		t.Errorf("document did not survive the round trip: %+v", doc)
+	}
+}
+
+func TestBuildCache_CorruptEntry(t *testing.T) {
+	fs := NewMemoryFileSystem()
+	fs.WriteFile(".skillgen-cache/patterns.json", []byte("{not json"), 0644)
+
+	if _, err := NewBuildCache(fs, ".skillgen-cache").Load("patterns"); err == nil {
+		t.Fatal("expected an error for a corrupt entry")
+	}
+}
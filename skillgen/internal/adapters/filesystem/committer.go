@@ -0,0 +1,201 @@
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// OutputCommitter implements ports.OutputCommitter on the OS filesystem.
+//
+// A commit runs in two phases. First every changed file is written to a
+// unique temp file beside its target; a failure there discards the temp
+// files and leaves the output exactly as it was. Then each temp file is
+// renamed over its target and each stale file deleted; a failure there
+// restores every file already swapped from its previous content.
+type OutputCommitter struct {
+	rename func(oldPath, newPath string) error
+	remove func(path string) error
+}
+
+// Ensure OutputCommitter implements ports.OutputCommitter
+var _ ports.OutputCommitter = (*OutputCommitter)(nil)
+
+// NewOutputCommitter creates a committer that writes to the OS filesystem.
+func NewOutputCommitter() *OutputCommitter {
+	return &OutputCommitter{rename: os.Rename, remove: os.Remove}
+}
+
+// change is one file a commit writes or deletes, with what it held before
+// so the change can be undone.
+type change struct {
+	path     string
+	content  []byte
+	remove   bool
+	existed  bool
+	previous []byte
+	temp     string
+}
+
+// Commit writes the changed files in files and deletes the files under
+// ownedDirs that files no longer includes, all or nothing.
+func (c *OutputCommitter) Commit(files map[string][]byte, ownedDirs []string) (int, error) {
+	changes, err := plan(files, ownedDirs)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range changes {
+		ch := &changes[i]
+		if ch.remove {
+			continue
+		}
+		dir := filepath.Dir(ch.path)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			discard(changes)
+			return 0, fmt.Errorf("failed to create directory %s: %w", dir, err)
+		}
+		if ch.temp, err = writeTemp(ch.path, ch.content, 0644); err != nil {
+			discard(changes)
+			return 0, err
+		}
+	}
+
+	for i := range changes {
+		ch := &changes[i]
+		if ch.remove {
+			err = c.remove(ch.path)
+		} else if err = c.rename(ch.temp, ch.path); err == nil {
+			ch.temp = ""
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to replace %s: %w", ch.path, err)
+			if rollbackErr := c.rollback(changes[:i]); rollbackErr != nil {
+				err = errors.Join(err, fmt.Errorf("rollback incomplete: %w", rollbackErr))
+			}
+			discard(changes)
+			return 0, err
+		}
+	}
+
+	for _, dir := range ownedDirs {
+		if err := removeEmptyDirs(dir); err != nil {
+			return len(changes), fmt.Errorf("failed to remove empty directories in %s: %w", dir, err)
+		}
+	}
+
+	return len(changes), nil
+}
+
+// plan compares files and ownedDirs against disk, returning every file to
+// write or delete, sorted by path.
+func plan(files map[string][]byte, ownedDirs []string) ([]change, error) {
+	var changes []change
+
+	for path, content := range files {
+		previous, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		existed := err == nil
+		if existed && bytes.Equal(previous, content) {
+			continue
+		}
+		changes = append(changes, change{path: path, content: content, existed: existed, previous: previous})
+	}
+
+	for _, dir := range ownedDirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) && path == dir {
+				return filepath.SkipDir
+			}
+			if err != nil || d.IsDir() {
+				return err
+			}
+			if _, ok := files[path]; ok {
+				return nil
+			}
+			previous, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			changes = append(changes, change{path: path, remove: true, existed: true, previous: previous})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].path < changes[j].path })
+	return changes, nil
+}
+
+// rollback undoes applied changes, newest first: files that existed get
+// their previous content back, files that didn't are deleted again.
+func (c *OutputCommitter) rollback(applied []change) error {
+	var errs []error
+	for i := len(applied) - 1; i >= 0; i-- {
+		ch := applied[i]
+		if !ch.existed {
+			if err := c.remove(ch.path); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		temp, err := writeTemp(ch.path, ch.previous, 0644)
+		if err == nil {
+			if err = c.rename(temp, ch.path); err != nil {
+				os.Remove(temp)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// discard deletes every temp file not yet renamed into place.
+func discard(changes []change) {
+	for _, ch := range changes {
+		if ch.temp != "" {
+			os.Remove(ch.temp)
+		}
+	}
+}
+
+// removeEmptyDirs deletes directories under dir that no longer hold any
+// files, deepest first. dir itself is kept.
+func removeEmptyDirs(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		if err := removeEmptyDirs(path); err != nil {
+			return err
+		}
+		if children, err := os.ReadDir(path); err == nil && len(children) == 0 {
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
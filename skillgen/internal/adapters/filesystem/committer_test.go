@@ -0,0 +1,179 @@
+package filesystem
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeTree creates files under root, keyed by slash-separated relative path.
+func writeTree(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readTree returns every file under root, keyed by slash-separated
+// relative path.
+func readTree(t *testing.T, root string) map[string]string {
+	t.Helper()
+	files := make(map[string]string)
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func TestOutputCommitter_Commit(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"README.md":                           "old readme",
+		"plugins/a/skills/a/SKILL.md":         "same",
+		"plugins/a/skills/a/library/gone.md":  "stale",
+		"plugins/a/skills/old-skill/SKILL.md": "stale sibling",
+		"plugins/b/skills/b/SKILL.md":         "not owned",
+		"plugins/b/skills/b/library/extra.md": "not owned either",
+	})
+	unchanged, _ := os.Stat(filepath.Join(root, "plugins/a/skills/a/SKILL.md"))
+
+	files := map[string][]byte{
+		filepath.Join(root, "README.md"):                       []byte("new readme"),
+		filepath.Join(root, "plugins/a/skills/a/SKILL.md"):     []byte("same"),
+		filepath.Join(root, "plugins/a/skills/a/reference.md"): []byte("new"),
+	}
+	changed, err := NewOutputCommitter().Commit(files, []string{filepath.Join(root, "plugins/a/skills")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// README.md and reference.md written; gone.md and old-skill/SKILL.md
+	// deleted; SKILL.md left alone.
+	if changed != 4 {
+		t.Errorf("expected 4 changes, got %d", changed)
+	}
+
+	want := map[string]string{
+		"README.md":                           "new readme",
+		"plugins/a/skills/a/SKILL.md":         "same",
+		"plugins/a/skills/a/reference.md":     "new",
+		"plugins/b/skills/b/SKILL.md":         "not owned",
+		"plugins/b/skills/b/library/extra.md": "not owned either",
+	}
+	got := readTree(t, root)
+	if len(got) != len(want) {
+		t.Errorf("expected files %v, got %v", want, got)
+	}
+	for path, content := range want {
+		if got[path] != content {
+			t.Errorf("%s: expected %q, got %q", path, content, got[path])
+		}
+	}
+
+	for _, dir := range []string{"plugins/a/skills/a/library", "plugins/a/skills/old-skill"} {
+		if _, err := os.Stat(filepath.Join(root, dir)); !os.IsNotExist(err) {
+			t.Errorf("expected empty directory %s to be removed", dir)
+		}
+	}
+	if after, _ := os.Stat(filepath.Join(root, "plugins/a/skills/a/SKILL.md")); !after.ModTime().Equal(unchanged.ModTime()) {
+		t.Error("expected the unchanged file not to be rewritten")
+	}
+}
+
+func TestOutputCommitter_StagingFailureLeavesOutputUntouched(t *testing.T) {
+	root := t.TempDir()
+	before := map[string]string{
+		"README.md": "old readme",
+		"blocked":   "a file where a directory is needed",
+	}
+	writeTree(t, root, before)
+
+	files := map[string][]byte{
+		filepath.Join(root, "README.md"):        []byte("new readme"),
+		filepath.Join(root, "blocked/SKILL.md"): []byte("unwritable"),
+	}
+	if _, err := NewOutputCommitter().Commit(files, nil); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	got := readTree(t, root)
+	if len(got) != len(before) || got["README.md"] != "old readme" {
+		t.Errorf("expected the output to be untouched, got %v", got)
+	}
+}
+
+func TestOutputCommitter_SwapFailureRollsBack(t *testing.T) {
+	root := t.TempDir()
+	before := map[string]string{
+		"a.md":        "old a",
+		"skills/x.md": "stale",
+		"z.md":        "old z",
+	}
+	writeTree(t, root, before)
+
+	// Fail the last swap, after a.md, b.md, and the removal of x.md.
+	committer := NewOutputCommitter()
+	committer.rename = func(oldPath, newPath string) error {
+		if filepath.Base(newPath) == "z.md" {
+			return errors.New("disk full")
+		}
+		return os.Rename(oldPath, newPath)
+	}
+
+	files := map[string][]byte{
+		filepath.Join(root, "a.md"): []byte("new a"),
+		filepath.Join(root, "b.md"): []byte("new b"),
+		filepath.Join(root, "z.md"): []byte("new z"),
+	}
+	_, err := committer.Commit(files, []string{filepath.Join(root, "skills")})
+	if err == nil || !strings.Contains(err.Error(), "disk full") {
+		t.Fatalf("expected the swap error, got %v", err)
+	}
+
+	got := readTree(t, root)
+	if len(got) != len(before) {
+		t.Errorf("expected exactly the original files, got %v", got)
+	}
+	for path, content := range before {
+		if got[path] != content {
+			t.Errorf("%s: expected %q after rollback, got %q", path, content, got[path])
+		}
+	}
+}
+
+func TestFileSystem_WriteFileLeavesNoTempFiles(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "out", "file.md")
+
+	fs := NewFileSystem()
+	for _, content := range []string{"first", "second"} {
+		if err := fs.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	got := readTree(t, root)
+	if len(got) != 1 || got["out/file.md"] != "second" {
+		t.Errorf("expected only out/file.md with the last content, got %v", got)
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
 )
 
 // FileSystem implements ports.FileSystem using the OS filesystem.
@@ -22,7 +25,9 @@ func (f *FileSystem) ReadFile(path string) ([]byte, error) {
 }
 
 // WriteFile writes data to a file atomically.
-// Writes to a temp file first, then renames to avoid partial writes.
+// Writes to a uniquely named temp file first, then renames it into place,
+// so a reader never sees a partial write and concurrent writers of the same
+// path never share a temp file.
 func (f *FileSystem) WriteFile(path string, data []byte, perm int) error {
 	// Validate path to prevent directory traversal
 	cleanPath := filepath.Clean(path)
@@ -33,10 +38,9 @@ func (f *FileSystem) WriteFile(path string, data []byte, perm int) error {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
-	// Write to temp file first for atomic operation
-	tempPath := cleanPath + ".tmp"
-	if err := os.WriteFile(tempPath, data, fs.FileMode(perm)); err != nil {
-		return fmt.Errorf("failed to write temp file %s: %w", tempPath, err)
+	tempPath, err := writeTemp(cleanPath, data, fs.FileMode(perm))
+	if err != nil {
+		return err
 	}
 
 	// Atomic rename
@@ -48,6 +52,30 @@ func (f *FileSystem) WriteFile(path string, data []byte, perm int) error {
 	return nil
 }
 
+// writeTemp writes data to a new temp file in path's directory, named after
+// path but unique to this call, and returns the temp file's path. Being in
+// the same directory, it can be renamed over path atomically.
+func writeTemp(path string, data []byte, perm fs.FileMode) (string, error) {
+	temp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file for %s: %w", path, err)
+	}
+
+	_, err = temp.Write(data)
+	if err == nil {
+		err = temp.Chmod(perm)
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return "", fmt.Errorf("failed to write temp file %s: %w", temp.Name(), err)
+	}
+
+	return temp.Name(), nil
+}
+
 // MkdirAll creates all directories in the path with the given permissions.
 func (f *FileSystem) MkdirAll(path string, perm int) error {
 	return os.MkdirAll(path, fs.FileMode(perm))
@@ -78,21 +106,28 @@ func (f *FileSystem) RemoveAll(path string) error {
 	return os.RemoveAll(path)
 }
 
-// FindIndexFiles recursively finds all index.md files in the given categories.
-// Categories are subdirectories under rootPath (e.g., "patterns", "enforce", "build", "secure").
-func FindIndexFiles(filesystem *FileSystem, rootPath string, categories []string) ([]string, error) {
-	var indexFiles []string
+// WalkDir walks the tree rooted at root with filepath.WalkDir.
+func (f *FileSystem) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(root, fn)
+}
 
-	for _, category := range categories {
-		categoryPath := filepath.Join(rootPath, category)
+// FindDocuments recursively finds all markdown pages in the given categories.
+// index.md and sibling pages such as checkpoints.md are all documents.
+// Each category is walked from its source directory under rootPath, through
+// the port, so discovery works the same on any ports.FileSystem. A category
+// whose source directory doesn't exist is an error: it is configured, so
+// its docs are expected.
+func FindDocuments(filesystem ports.FileSystem, rootPath string, categories domain.CategorySet) ([]string, error) {
+	var documents []string
 
-		// Skip if category directory doesn't exist
-		if !filesystem.Exists(categoryPath) {
-			continue
+	for _, category := range categories {
+		categoryPath := filepath.Join(rootPath, filepath.FromSlash(category.SourceDir))
+		if !filesystem.IsDir(categoryPath) {
+			return nil, fmt.Errorf("category %s source directory %s does not exist", category.Name, categoryPath)
 		}
 
 		// Walk the category directory
-		err := filepath.WalkDir(categoryPath, func(path string, d fs.DirEntry, err error) error {
+		err := filesystem.WalkDir(categoryPath, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
@@ -102,49 +137,58 @@ func FindIndexFiles(filesystem *FileSystem, rootPath string, categories []string
 				return nil
 			}
 
-			// Only match index.md files
-			if strings.ToLower(d.Name()) == "index.md" {
-				absPath, err := filepath.Abs(path)
-				if err != nil {
-					return err
-				}
-				indexFiles = append(indexFiles, absPath)
+			// Only match markdown pages
+			if strings.EqualFold(filepath.Ext(d.Name()), ".md") {
+				documents = append(documents, path)
 			}
 
 			return nil
 		})
 
 		if err != nil {
-			return nil, fmt.Errorf("failed to walk category %s: %w", category, err)
+			return nil, fmt.Errorf("failed to walk category %s: %w", category.Name, err)
 		}
 	}
 
-	return indexFiles, nil
+	return documents, nil
 }
 
-// DetermineCategory extracts the category name from a file path.
-// Example: "/docs/patterns/idempotency/index.md" -> "patterns"
-func DetermineCategory(path string, categories []string) string {
-	cleanPath := filepath.Clean(path)
-	parts := strings.Split(cleanPath, string(filepath.Separator))
-
-	// Find the category in the path
-	for i, part := range parts {
-		for _, category := range categories {
-			if part == category {
-				return category
-			}
+// ReadDocsTree lists every file under rootPath, through the port, so links
+// between pages can be checked against the whole docs root and not only
+// the categories being generated.
+func ReadDocsTree(filesystem ports.FileSystem, rootPath string) (*domain.DocsTree, error) {
+	var paths []string
+	err := filesystem.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
 		}
-
-		// Also check if this is right after "docs"
-		if i > 0 && parts[i-1] == "docs" {
-			for _, category := range categories {
-				if part == category {
-					return category
-				}
-			}
+		if d.IsDir() {
+			return nil
 		}
+		rel, err := filepath.Rel(rootPath, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk docs root %s: %w", rootPath, err)
 	}
+	return domain.NewDocsTree(paths), nil
+}
 
-	return ""
+// DetermineCategory extracts the category name from the path of a file
+// under rootPath, the docs root.
+// Example: "/docs/patterns/idempotency/index.md" -> "patterns"
+func DetermineCategory(rootPath, path string, categories domain.CategorySet) string {
+	rel, err := filepath.Rel(rootPath, path)
+	if err != nil {
+		return ""
+	}
+	category, _, ok := categories.Locate(rel)
+	if !ok {
+		return ""
+	}
+	return category.Name
 }
@@ -0,0 +1,50 @@
+package filesystem
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// GeneratedManifestStore implements ports.GeneratedManifestStore as a JSON
+// file, formatted like marketplace.json so it diffs cleanly when committed.
+type GeneratedManifestStore struct {
+	fs ports.FileSystem
+}
+
+// NewGeneratedManifestStore creates a manifest store on fs.
+func NewGeneratedManifestStore(fs ports.FileSystem) *GeneratedManifestStore {
+	return &GeneratedManifestStore{fs: fs}
+}
+
+// Read reads the manifest at path.
+func (s *GeneratedManifestStore) Read(path string) (*domain.GeneratedManifest, error) {
+	content, err := s.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generated-files manifest: %w", err)
+	}
+
+	var manifest domain.GeneratedManifest
+	if err := json.Unmarshal(content, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse generated-files manifest %s: %w", path, err)
+	}
+
+	return &manifest, nil
+}
+
+// Write writes the manifest to path.
+func (s *GeneratedManifestStore) Write(manifest *domain.GeneratedManifest, path string) error {
+	content, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal generated-files manifest: %w", err)
+	}
+	content = append(content, '\n')
+
+	if err := s.fs.WriteFile(path, content, 0644); err != nil {
+		return fmt.Errorf("failed to write generated-files manifest: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package filesystem
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReadGitTree loads the directory dir as it is at ref (a commit, tag, or
+// branch) from its git repository's object store, without reading or
+// touching the working tree. dir must lie inside a git working tree; its
+// files are loaded at the same paths they have on disk, so the rest of the
+// generator cannot tell the difference. It returns the tree and the full
+// SHA of the commit ref resolved to.
+func ReadGitTree(dir, ref string) (*MemoryFileSystem, string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+
+	commit, err := git(root, nil, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to resolve %q in %s: %w", ref, dir, err)
+	}
+	commit = strings.TrimSpace(commit)
+
+	// The prefix is dir's path inside the repository ("docs/"), which
+	// ls-tree needs to list just that subtree.
+	prefix, err := git(root, nil, "rev-parse", "--show-prefix")
+	if err != nil {
+		return nil, "", fmt.Errorf("%s is not inside a git working tree: %w", dir, err)
+	}
+	prefix = strings.TrimSpace(prefix)
+
+	args := []string{"ls-tree", "-r", "-z", "--full-tree", commit}
+	if prefix != "" {
+		args = append(args, "--", prefix)
+	}
+	listing, err := git(root, nil, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list %s at %s: %w", dir, commit, err)
+	}
+
+	// Symlinks and submodules have no file content to read.
+	var paths, objects []string
+	for _, entry := range strings.Split(strings.TrimSuffix(listing, "\x00"), "\x00") {
+		meta, path, ok := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) != 3 || fields[1] != "blob" || fields[0] == "120000" {
+			continue
+		}
+		paths = append(paths, strings.TrimPrefix(path, prefix))
+		objects = append(objects, fields[2])
+	}
+
+	if len(objects) == 0 {
+		return nil, "", fmt.Errorf("%s has no files at %s", dir, commit)
+	}
+
+	contents, err := readBlobs(root, objects)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s at %s: %w", dir, commit, err)
+	}
+	tree := NewMemoryFileSystem()
+	for i, path := range paths {
+		tree.WriteFile(filepath.Join(root, filepath.FromSlash(path)), contents[i], 0644)
+	}
+
+	return tree, commit, nil
+}
+
+// readBlobs reads every object in one "git cat-file --batch" process,
+// returning each one's content in order.
+func readBlobs(dir string, objects []string) ([][]byte, error) {
+	out, err := git(dir, strings.NewReader(strings.Join(objects, "\n")+"\n"), "cat-file", "--batch")
+	if err != nil {
+		return nil, err
+	}
+
+	// Each object comes back as "<sha> <type> <size>\n<content>\n".
+	r := bufio.NewReader(strings.NewReader(out))
+	contents := make([][]byte, len(objects))
+	for i, object := range objects {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("truncated output for %s: %w", object, err)
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("object %s: %s", object, strings.TrimSpace(header))
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("object %s: bad size %q", object, fields[2])
+		}
+
+		contents[i] = make([]byte, size)
+		if _, err := io.ReadFull(r, contents[i]); err != nil {
+			return nil, fmt.Errorf("truncated content for %s: %w", object, err)
+		}
+		if _, err := r.Discard(1); err != nil {
+			return nil, fmt.Errorf("truncated content for %s: %w", object, err)
+		}
+	}
+
+	return contents, nil
+}
+
+// git runs a git command in dir and returns its standard output, or an
+// error carrying its standard error.
+func git(dir string, stdin io.Reader, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdin = stdin
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,77 @@
+package filesystem
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runGit runs a git command in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestReadGitTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	repo := t.TempDir()
+	runGit(t, repo, "init", "-q")
+	writeTree(t, repo, map[string]string{
+		"docs/patterns/index.md":   "# Patterns v1\n",
+		"docs/patterns/a/index.md": "# A\n",
+		"README.md":                "outside docs/",
+	})
+	runGit(t, repo, "add", "-A")
+	runGit(t, repo, "commit", "-q", "-m", "v1")
+	runGit(t, repo, "tag", "v1")
+	first := runGit(t, repo, "rev-parse", "HEAD")
+
+	// A later commit and uncommitted edits must not show through.
+	writeTree(t, repo, map[string]string{"docs/patterns/index.md": "# Patterns v2\n"})
+	runGit(t, repo, "commit", "-q", "-am", "v2")
+	writeTree(t, repo, map[string]string{"docs/patterns/b/index.md": "# Uncommitted\n"})
+
+	docs := filepath.Join(repo, "docs")
+	tree, commit, err := ReadGitTree(docs, "v1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commit != first {
+		t.Errorf("expected commit %s, got %s", first, commit)
+	}
+
+	content, err := tree.ReadFile(filepath.Join(docs, "patterns", "index.md"))
+	if err != nil || string(content) != "# Patterns v1\n" {
+		t.Errorf("expected the v1 content, got %q, %v", content, err)
+	}
+
+	files, err := FindDocuments(tree, docs, discoveryCategories[:1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{filepath.Join(docs, "patterns", "a", "index.md"), filepath.Join(docs, "patterns", "index.md")}
+	if strings.Join(files, ",") != strings.Join(want, ",") {
+		t.Errorf("expected %v, got %v", want, files)
+	}
+	if tree.Exists(filepath.Join(repo, "README.md")) {
+		t.Error("expected only the docs/ subtree to be loaded")
+	}
+
+	if _, _, err := ReadGitTree(docs, "no-such-ref"); err == nil {
+		t.Error("expected an error for an unknown ref")
+	}
+}
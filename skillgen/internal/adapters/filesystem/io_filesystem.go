@@ -0,0 +1,125 @@
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// errReadOnly is returned for every write to an IOFileSystem.
+var errReadOnly = errors.New("read-only filesystem")
+
+// IOFileSystem implements ports.FileSystem, read-only, over an io/fs.FS: an
+// embed.FS, an fstest.MapFS, a zip.Reader, or anything else with that
+// interface. The tree appears mounted at root, so callers keep using the
+// same OS-style paths they would on disk.
+type IOFileSystem struct {
+	fsys fs.FS
+	root string
+}
+
+// Ensure IOFileSystem implements ports.FileSystem
+var _ ports.FileSystem = (*IOFileSystem)(nil)
+
+// NewIOFileSystem creates a read-only filesystem presenting fsys at root.
+func NewIOFileSystem(fsys fs.FS, root string) *IOFileSystem {
+	return &IOFileSystem{fsys: fsys, root: filepath.Clean(root)}
+}
+
+// ReadFile reads the entire file content at the given path.
+func (f *IOFileSystem) ReadFile(path string) ([]byte, error) {
+	name, err := f.name("read", path)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(f.fsys, name)
+}
+
+// WriteFile always fails: an IOFileSystem is read-only.
+func (f *IOFileSystem) WriteFile(path string, data []byte, perm int) error {
+	return &fs.PathError{Op: "write", Path: path, Err: errReadOnly}
+}
+
+// MkdirAll always fails: an IOFileSystem is read-only.
+func (f *IOFileSystem) MkdirAll(path string, perm int) error {
+	return &fs.PathError{Op: "mkdir", Path: path, Err: errReadOnly}
+}
+
+// Glob returns all files and directories matching the pattern.
+func (f *IOFileSystem) Glob(pattern string) ([]string, error) {
+	name, err := f.name("glob", pattern)
+	if err != nil {
+		return nil, nil
+	}
+
+	matches, err := fs.Glob(f.fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	for i, match := range matches {
+		matches[i] = f.path(match)
+	}
+	return matches, nil
+}
+
+// Exists returns true if the path is a file or directory in the tree.
+func (f *IOFileSystem) Exists(path string) bool {
+	_, err := f.stat(path)
+	return err == nil
+}
+
+// IsDir returns true if the path is a directory in the tree.
+func (f *IOFileSystem) IsDir(path string) bool {
+	info, err := f.stat(path)
+	return err == nil && info.IsDir()
+}
+
+// RemoveAll always fails: an IOFileSystem is read-only.
+func (f *IOFileSystem) RemoveAll(path string) error {
+	return &fs.PathError{Op: "remove", Path: path, Err: errReadOnly}
+}
+
+// WalkDir walks the tree rooted at root with fs.WalkDir, passing fn paths
+// under the mount point rather than the io/fs names.
+func (f *IOFileSystem) WalkDir(root string, fn fs.WalkDirFunc) error {
+	name, err := f.name("walk", root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+
+	return fs.WalkDir(f.fsys, name, func(name string, d fs.DirEntry, err error) error {
+		return fn(f.path(name), d, err)
+	})
+}
+
+// stat returns the file info for path.
+func (f *IOFileSystem) stat(path string) (fs.FileInfo, error) {
+	name, err := f.name("stat", path)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Stat(f.fsys, name)
+}
+
+// name converts an OS-style path under the mount point to an io/fs name.
+// Paths outside the mount point do not exist.
+func (f *IOFileSystem) name(op, path string) (string, error) {
+	rel, err := filepath.Rel(f.root, filepath.Clean(path))
+	if err != nil {
+		return "", &fs.PathError{Op: op, Path: path, Err: fs.ErrNotExist}
+	}
+
+	name := filepath.ToSlash(rel)
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: path, Err: fmt.Errorf("outside %s: %w", f.root, fs.ErrNotExist)}
+	}
+	return name, nil
+}
+
+// path converts an io/fs name back to a path under the mount point.
+func (f *IOFileSystem) path(name string) string {
+	return filepath.Join(f.root, filepath.FromSlash(name))
+}
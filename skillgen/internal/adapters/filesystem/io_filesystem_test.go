@@ -0,0 +1,171 @@
+package filesystem
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+var discoveryCategories = domain.CategorySet{
+	{Name: "patterns", SourceDir: "patterns"},
+	{Name: "ops", SourceDir: "guides/ops"},
+}
+
+// discoveryTree is a docs tree, keyed by slash-separated path under the
+// docs root.
+var discoveryTree = map[string]string{
+	"patterns/index.md":        "# Patterns",
+	"patterns/a/index.md":      "# A",
+	"patterns/a/notes.md":      "# Notes",
+	"patterns/a/diagram.svg":   "<svg/>",
+	"patterns/b/c/INDEX.md":    "# C",
+	"guides/ops/index.md":      "# Ops",
+	"guides/other/index.md":    "# Not a category",
+	"blog/posts/2024/index.md": "# Blog",
+}
+
+var wantDocuments = []string{
+	"/docs/patterns/a/index.md",
+	"/docs/patterns/a/notes.md",
+	"/docs/patterns/b/c/INDEX.md",
+	"/docs/patterns/index.md",
+	"/docs/guides/ops/index.md",
+}
+
+func TestFindDocuments_AnyFileSystem(t *testing.T) {
+	mapFS := fstest.MapFS{}
+	memFS := NewMemoryFileSystem()
+	mockFS := NewMockFileSystem()
+	for path, content := range discoveryTree {
+		mapFS[path] = &fstest.MapFile{Data: []byte(content)}
+		memFS.WriteFile("/docs/"+path, []byte(content), 0644)
+		mockFS.AddFile("/docs/"+path, []byte(content))
+		for dir := filepath.Dir("/docs/" + path); dir != "/"; dir = filepath.Dir(dir) {
+			mockFS.MkdirAll(dir, 0755)
+		}
+	}
+
+	tests := []struct {
+		name string
+		find func() ([]string, error)
+	}{
+		{"io/fs", func() ([]string, error) {
+			return FindDocuments(NewIOFileSystem(mapFS, "/docs"), "/docs", discoveryCategories)
+		}},
+		{"memory", func() ([]string, error) {
+			return FindDocuments(memFS, "/docs", discoveryCategories)
+		}},
+		{"document reader over a mock", func() ([]string, error) {
+			reader := NewDocumentReader(mockFS, nil, nil, nil, nil, nil, discoveryCategories)
+			return reader.ListDocuments("/docs", discoveryCategories)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.find()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, wantDocuments) {
+				t.Errorf("expected %v, got %v", wantDocuments, got)
+			}
+		})
+	}
+}
+
+func TestFindDocuments_MissingCategoryDirectory(t *testing.T) {
+	memFS := NewMemoryFileSystem()
+	for path, content := range discoveryTree {
+		memFS.WriteFile("/docs/"+path, []byte(content), 0644)
+	}
+
+	categories := append(discoveryCategories[:len(discoveryCategories):len(discoveryCategories)], domain.Category{Name: "missing", SourceDir: "missing"})
+	if _, err := FindDocuments(memFS, "/docs", categories); err == nil {
+		t.Fatal("expected an error for a category whose source directory does not exist")
+	}
+}
+
+func TestReadDocsTree(t *testing.T) {
+	memFS := NewMemoryFileSystem()
+	for path, content := range discoveryTree {
+		memFS.WriteFile("/docs/"+path, []byte(content), 0644)
+	}
+
+	tree, err := ReadDocsTree(memFS, "/docs")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !tree.Has("patterns/a/diagram.svg") || !tree.Has("blog/posts/2024/index.md") {
+		t.Error("expected every file under the docs root, in any category or none")
+	}
+	if tree.Has("patterns/a") || tree.Has("/docs/patterns/index.md") {
+		t.Error("expected only files, by path relative to the docs root")
+	}
+	if got := len(tree.Paths()); got != len(discoveryTree) {
+		t.Errorf("expected %d files, got %d", len(discoveryTree), got)
+	}
+}
+
+func TestIOFileSystem(t *testing.T) {
+	fsys := NewIOFileSystem(fstest.MapFS{
+		"patterns/index.md": &fstest.MapFile{Data: []byte("# Patterns")},
+	}, "/docs")
+
+	content, err := fsys.ReadFile("/docs/patterns/index.md")
+	if err != nil || string(content) != "# Patterns" {
+		t.Errorf("expected the file's content, got %q, %v", content, err)
+	}
+	if !fsys.IsDir("/docs/patterns") || fsys.IsDir("/docs/patterns/index.md") {
+		t.Error("expected patterns/ to be the only directory")
+	}
+	if fsys.Exists("/elsewhere/patterns/index.md") {
+		t.Error("expected paths outside the mount point not to exist")
+	}
+
+	matches, err := fsys.Glob("/docs/patterns/*.md")
+	if err != nil || !reflect.DeepEqual(matches, []string{"/docs/patterns/index.md"}) {
+		t.Errorf("expected the mounted path, got %v, %v", matches, err)
+	}
+
+	if err := fsys.WriteFile("/docs/patterns/new.md", nil, 0644); !errors.Is(err, errReadOnly) {
+		t.Errorf("expected a read-only error, got %v", err)
+	}
+}
+
+func TestMemoryFileSystem_WalkDir(t *testing.T) {
+	memFS := NewMemoryFileSystem()
+	for _, path := range []string{"root/b.md", "root/a/x.md", "root/skip/y.md", "root/c/z.md"} {
+		memFS.WriteFile(path, []byte(path), 0644)
+	}
+
+	var visited []string
+	err := memFS.WalkDir("root", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == "skip" {
+			return fs.SkipDir
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"root", "root/a", "root/a/x.md", "root/b.md", "root/c", "root/c/z.md"}
+	if !reflect.DeepEqual(visited, want) {
+		t.Errorf("expected %v, got %v", want, visited)
+	}
+
+	err = memFS.WalkDir("nowhere", func(path string, d fs.DirEntry, err error) error { return err })
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("expected a not-exist error for a missing root, got %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package filesystem
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// MemoryFileSystem implements ports.FileSystem entirely in memory. It lets
+// the full generation pipeline run without touching disk, so its output can
+// be compared against what is committed (see services.DriftChecker).
+type MemoryFileSystem struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+// Ensure MemoryFileSystem implements ports.FileSystem
+var _ ports.FileSystem = (*MemoryFileSystem)(nil)
+
+// NewMemoryFileSystem creates an empty in-memory filesystem.
+func NewMemoryFileSystem() *MemoryFileSystem {
+	return &MemoryFileSystem{
+		files: make(map[string][]byte),
+		dirs:  make(map[string]bool),
+	}
+}
+
+// ReadFile reads the entire file content at the given path.
+func (m *MemoryFileSystem) ReadFile(path string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	content, ok := m.files[filepath.Clean(path)]
+	if !ok {
+		return nil, fmt.Errorf("file not found: %s", path)
+	}
+
+	return append([]byte(nil), content...), nil
+}
+
+// WriteFile stores a copy of data at path, creating parent directories as
+// the OS adapter does.
+func (m *MemoryFileSystem) WriteFile(path string, data []byte, perm int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cleanPath := filepath.Clean(path)
+	m.files[cleanPath] = append([]byte(nil), data...)
+	m.addDirs(filepath.Dir(cleanPath))
+
+	return nil
+}
+
+// MkdirAll creates all directories in the path.
+func (m *MemoryFileSystem) MkdirAll(path string, perm int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.addDirs(filepath.Clean(path))
+	return nil
+}
+
+// Glob returns all files and directories matching the pattern, using
+// filepath.Match semantics.
+func (m *MemoryFileSystem) Glob(pattern string) ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pattern = filepath.Clean(pattern)
+
+	var matches []string
+	for _, paths := range []map[string]bool{m.fileSet(), m.dirs} {
+		for path := range paths {
+			ok, err := filepath.Match(pattern, path)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				matches = append(matches, path)
+			}
+		}
+	}
+	sort.Strings(matches)
+
+	return matches, nil
+}
+
+// Exists returns true if the path is a known file or directory.
+func (m *MemoryFileSystem) Exists(path string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	cleanPath := filepath.Clean(path)
+	_, isFile := m.files[cleanPath]
+	return isFile || m.dirs[cleanPath]
+}
+
+// IsDir returns true if the path is a known directory.
+func (m *MemoryFileSystem) IsDir(path string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return m.dirs[filepath.Clean(path)]
+}
+
+// RemoveAll removes path and any children it contains.
+func (m *MemoryFileSystem) RemoveAll(path string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cleanPath := filepath.Clean(path)
+	prefix := cleanPath + string(filepath.Separator)
+	for p := range m.files {
+		if p == cleanPath || strings.HasPrefix(p, prefix) {
+			delete(m.files, p)
+		}
+	}
+	for p := range m.dirs {
+		if p == cleanPath || strings.HasPrefix(p, prefix) {
+			delete(m.dirs, p)
+		}
+	}
+
+	return nil
+}
+
+// WalkDir walks the tree rooted at root in lexical order, as
+// filepath.WalkDir does on disk. It walks a snapshot, so fn may write to
+// the filesystem without affecting the walk.
+func (m *MemoryFileSystem) WalkDir(root string, fn fs.WalkDirFunc) error {
+	root = filepath.Clean(root)
+
+	m.mu.RLock()
+	children := make(map[string][]fs.DirEntry)
+	for path, content := range m.files {
+		parent := filepath.Dir(path)
+		children[parent] = append(children[parent], memEntry(filepath.Base(path), int64(len(content)), false))
+	}
+	for path := range m.dirs {
+		parent := filepath.Dir(path)
+		children[parent] = append(children[parent], memEntry(filepath.Base(path), 0, true))
+	}
+	content, isFile := m.files[root]
+	isDir := m.dirs[root] || root == "." || root == string(filepath.Separator)
+	m.mu.RUnlock()
+
+	for _, entries := range children {
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	}
+
+	var err error
+	switch {
+	case isDir:
+		err = walkMemory(root, memEntry(filepath.Base(root), 0, true), children, fn)
+	case isFile:
+		err = walkMemory(root, memEntry(filepath.Base(root), int64(len(content)), false), children, fn)
+	default:
+		err = fn(root, nil, &fs.PathError{Op: "lstat", Path: root, Err: fs.ErrNotExist})
+	}
+	if err == fs.SkipDir || err == fs.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walkMemory visits path, then, if it is a directory, each of its children.
+func walkMemory(path string, d fs.DirEntry, children map[string][]fs.DirEntry, fn fs.WalkDirFunc) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if err == fs.SkipDir && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	for _, child := range children[path] {
+		if err := walkMemory(filepath.Join(path, child.Name()), child, children, fn); err != nil {
+			if err == fs.SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// memEntry describes a file or directory of a MemoryFileSystem.
+func memEntry(name string, size int64, dir bool) fs.DirEntry {
+	return fs.FileInfoToDirEntry(memFileInfo{name: name, size: size, dir: dir})
+}
+
+// memFileInfo is the fs.FileInfo of a MemoryFileSystem entry.
+type memFileInfo struct {
+	name string
+	size int64
+	dir  bool
+}
+
+func (i memFileInfo) Name() string       { return i.name }
+func (i memFileInfo) Size() int64        { return i.size }
+func (i memFileInfo) ModTime() time.Time { return time.Time{} }
+func (i memFileInfo) IsDir() bool        { return i.dir }
+func (i memFileInfo) Sys() any           { return nil }
+
+func (i memFileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0755
+	}
+	return 0644
+}
+
+// Files returns a copy of every file written, keyed by cleaned path.
+func (m *MemoryFileSystem) Files() map[string][]byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	files := make(map[string][]byte, len(m.files))
+	for path, content := range m.files {
+		files[path] = append([]byte(nil), content...)
+	}
+	return files
+}
+
+// addDirs records dir and all of its ancestors. Callers must hold mu.
+func (m *MemoryFileSystem) addDirs(dir string) {
+	for dir != "." && dir != string(filepath.Separator) && !m.dirs[dir] {
+		m.dirs[dir] = true
+		dir = filepath.Dir(dir)
+	}
+}
+
+// fileSet returns the file paths as a set. Callers must hold mu.
+func (m *MemoryFileSystem) fileSet() map[string]bool {
+	set := make(map[string]bool, len(m.files))
+	for path := range m.files {
+		set[path] = true
+	}
+	return set
+}
@@ -129,6 +129,22 @@ func (m *MockFileSystem) RemoveAll(path string) error {
 	return nil
 }
 
+// WalkDir walks the mock's files and directories as a MemoryFileSystem
+// holding the same tree would.
+func (m *MockFileSystem) WalkDir(root string, fn fs.WalkDirFunc) error {
+	m.mu.RLock()
+	tree := NewMemoryFileSystem()
+	for path, content := range m.files {
+		tree.WriteFile(path, content, 0644)
+	}
+	for path := range m.dirs {
+		tree.MkdirAll(path, 0755)
+	}
+	m.mu.RUnlock()
+
+	return tree.WalkDir(root, fn)
+}
+
 // AddFile seeds the filesystem with a file for testing.
 func (m *MockFileSystem) AddFile(path string, content []byte) {
 	m.mu.Lock()
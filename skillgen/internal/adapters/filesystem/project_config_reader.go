@@ -0,0 +1,48 @@
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// ProjectConfigReader implements ports.ProjectConfigReader using the
+// filesystem.
+type ProjectConfigReader struct {
+	fs ports.FileSystem
+}
+
+// NewProjectConfigReader creates a new filesystem-based skillgen.yaml reader.
+func NewProjectConfigReader(fs ports.FileSystem) *ProjectConfigReader {
+	return &ProjectConfigReader{fs: fs}
+}
+
+// ReadProjectConfig reads and parses skillgen.yaml. An empty file is a valid,
+// empty config.
+func (r *ProjectConfigReader) ReadProjectConfig(path string) (*domain.ProjectConfig, error) {
+	content, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var cfg domain.ProjectConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	for _, category := range cfg.Categories {
+		if category == "" {
+			return nil, fmt.Errorf("categories cannot contain an empty name in %s", path)
+		}
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,118 @@
+package filesystem
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+func TestProjectConfigReader_ReadProjectConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     *string
+		wantErr     bool
+		errContains string
+		validate    func(*testing.T, *domain.ProjectConfig)
+	}{
+		{
+			name: "every section",
+			content: ptr(`paths:
+  source: ../docs
+  output: plugins
+  templates: skillgen/templates
+  mkdocs_config: ../mkdocs.yml
+  snippets: [docs, includes]
+verbose: true
+categories: [patterns, build]
+base_urls:
+  docs: https://docs.example.com
+output:
+  fail_on: error
+`),
+			validate: func(t *testing.T, cfg *domain.ProjectConfig) {
+				if cfg.Paths.Source != "../docs" || cfg.Paths.Output != "plugins" || cfg.Paths.Templates != "skillgen/templates" {
+					t.Errorf("unexpected paths: %+v", cfg.Paths)
+				}
+				if cfg.Paths.MkDocsConfig != "../mkdocs.yml" {
+					t.Errorf("unexpected mkdocs_config: %q", cfg.Paths.MkDocsConfig)
+				}
+				if strings.Join(cfg.Paths.Snippets, ",") != "docs,includes" {
+					t.Errorf("unexpected snippets: %q", cfg.Paths.Snippets)
+				}
+				if cfg.Verbose == nil || !*cfg.Verbose {
+					t.Error("expected verbose to be set to true")
+				}
+				if len(cfg.Categories) != 2 || cfg.Categories[0] != "patterns" || cfg.Categories[1] != "build" {
+					t.Errorf("unexpected categories: %v", cfg.Categories)
+				}
+				if cfg.BaseURLs.Docs != "https://docs.example.com" {
+					t.Errorf("unexpected docs base URL: %q", cfg.BaseURLs.Docs)
+				}
+				if cfg.Output.FailOn != "error" {
+					t.Errorf("unexpected fail_on: %q", cfg.Output.FailOn)
+				}
+			},
+		},
+		{
+			name:    "empty file",
+			content: ptr(""),
+			validate: func(t *testing.T, cfg *domain.ProjectConfig) {
+				if cfg.Verbose != nil {
+					t.Error("expected verbose to be unset")
+				}
+				if cfg.Paths.Source != "" {
+					t.Errorf("expected no source, got %q", cfg.Paths.Source)
+				}
+			},
+		},
+		{
+			name:        "unknown key",
+			content:     ptr("paths:\n  sorce: ../docs\n"),
+			wantErr:     true,
+			errContains: "failed to parse skillgen.yaml",
+		},
+		{
+			name:        "empty category",
+			content:     ptr("categories: [patterns, \"\"]\n"),
+			wantErr:     true,
+			errContains: "categories cannot contain an empty name",
+		},
+		{
+			name:        "file not found",
+			wantErr:     true,
+			errContains: "failed to read skillgen.yaml",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockFS := NewMockFileSystem()
+			if tt.content != nil {
+				mockFS.AddFile("skillgen.yaml", []byte(*tt.content))
+			}
+
+			cfg, err := NewProjectConfigReader(mockFS).ReadProjectConfig("skillgen.yaml")
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error containing %q, got nil", tt.errContains)
+					return
+				}
+				if !contains(err.Error(), tt.errContains) {
+					t.Errorf("expected error containing %q, got %q", tt.errContains, err.Error())
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			tt.validate(t, cfg)
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -2,33 +2,47 @@ package filesystem
 
 import (
 	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
 )
 
+// urlScheme matches the scheme of an absolute URL, such as "https:" or
+// "mailto:".
+var urlScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
 // DocumentReader implements ports.DocumentReader using the filesystem.
 type DocumentReader struct {
 	fs                ports.FileSystem
 	frontmatterParser ports.FrontmatterParser
+	markdownParser    ports.MarkdownParser
 	sectionParser     ports.SectionParser
 	contentExtractor  ports.ContentExtractor
-	categories        []string
+	snippetResolver   ports.SnippetResolver
+	categories        domain.CategorySet
 }
 
 // NewDocumentReader creates a new filesystem-based document reader.
 func NewDocumentReader(
 	fs ports.FileSystem,
 	frontmatterParser ports.FrontmatterParser,
+	markdownParser ports.MarkdownParser,
 	sectionParser ports.SectionParser,
 	contentExtractor ports.ContentExtractor,
-	categories []string,
+	snippetResolver ports.SnippetResolver,
+	categories domain.CategorySet,
 ) *DocumentReader {
 	return &DocumentReader{
 		fs:                fs,
 		frontmatterParser: frontmatterParser,
+		markdownParser:    markdownParser,
 		sectionParser:     sectionParser,
 		contentExtractor:  contentExtractor,
+		snippetResolver:   snippetResolver,
 		categories:        categories,
 	}
 }
@@ -47,18 +61,53 @@ func (r *DocumentReader) ReadDocument(path string) (*domain.Document, error) {
 		return nil, fmt.Errorf("failed to parse frontmatter in %s: %w", path, err)
 	}
 
+	// Expand snippet includes, so every later step sees the content the
+	// site renders. Their lines count from the top of the file, frontmatter
+	// included, and so do those of the lines they map the result back to.
+	offset := strings.Count(string(content), "\n") - strings.Count(markdown, "\n")
+	markdown, snippets, lines := r.snippetResolver.Resolve(markdown)
+	for i := range snippets {
+		snippets[i].Line += offset
+	}
+	lines.Shift(offset)
+
+	// Parse the markdown once; sections and every content component are
+	// read from the same tree, so they agree on what is code, a table, or
+	// a heading.
+	parsed := r.markdownParser.Parse(markdown)
+
 	// Parse sections
-	sections, err := r.sectionParser.Parse(markdown)
+	sections, err := r.sectionParser.Parse(parsed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse sections in %s: %w", path, err)
 	}
 
 	// Extract content components
-	introduction := r.sectionParser.ExtractIntroduction(markdown)
-	codeBlocks := r.contentExtractor.ExtractCodeBlocks(markdown)
-	mermaid := r.contentExtractor.ExtractMermaid(markdown)
-	tables := r.contentExtractor.ExtractTables(markdown)
-	admonitions := r.contentExtractor.ExtractAdmonitions(markdown)
+	introduction := r.sectionParser.ExtractIntroduction(parsed)
+	codeBlocks := r.contentExtractor.ExtractCodeBlocks(parsed)
+	mermaid := r.contentExtractor.ExtractMermaid(parsed)
+	tables := r.contentExtractor.ExtractTables(parsed)
+	admonitions := r.contentExtractor.ExtractAdmonitions(parsed)
+	links := r.contentExtractor.ExtractLinks(parsed)
+
+	// Every position so far is in the parsed markdown; move each to the
+	// line of the file it came from.
+	locateSections(sections, lines)
+	for i := range codeBlocks {
+		codeBlocks[i].Position = lines.Locate(codeBlocks[i].Position)
+	}
+	for i := range mermaid {
+		mermaid[i].Position = lines.Locate(mermaid[i].Position)
+	}
+	for i := range tables {
+		tables[i].Position = lines.Locate(tables[i].Position)
+	}
+	for i := range admonitions {
+		admonitions[i].Position = lines.Locate(admonitions[i].Position)
+	}
+	for i := range links {
+		links[i].Position = lines.Locate(links[i].Position)
+	}
 
 	// Build document
 	doc := &domain.Document{
@@ -70,18 +119,61 @@ func (r *DocumentReader) ReadDocument(path string) (*domain.Document, error) {
 		Mermaid:      mermaid,
 		Tables:       tables,
 		Admonitions:  admonitions,
+		Snippets:     snippets,
+		Links:        links,
+		Assets:       localAssets(path, links),
 		RawContent:   markdown,
+		Lines:        lines,
 	}
 
 	return doc, nil
 }
 
-// ListIndexFiles finds all index.md files in the specified root path.
-func (r *DocumentReader) ListIndexFiles(rootPath string, categories []string) ([]string, error) {
-	osFS, ok := r.fs.(*FileSystem)
-	if !ok {
-		return nil, fmt.Errorf("filesystem type not supported for directory walking")
+// locateSections moves the position of each section, and its end line,
+// from the parsed markdown to the file, subsections too.
+func locateSections(sections []domain.Section, lines domain.SourceMap) {
+	for i := range sections {
+		s := &sections[i]
+		s.Position = lines.Locate(s.Position)
+		s.EndLine = lines.Locate(domain.Position{Line: s.EndLine}).Line
+		locateSections(s.SubSections, lines)
 	}
+}
+
+// localAssets returns the path of each local file, other than a page, that
+// a link in the document at path points to, once each, in link order, with
+// where the first link to it is. Whether the file exists is left to
+// whoever bundles it: the list is cached with the parse, and a file may
+// turn up later.
+// Example: "diagrams/flow.png#dark" in /docs/a/index.md -> /docs/a/diagrams/flow.png
+func localAssets(path string, links []domain.Link) []domain.AssetRef {
+	var assets []domain.AssetRef
+	seen := make(map[string]bool)
+	for _, link := range links {
+		dest := link.Dest
+		if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "/") || urlScheme.MatchString(dest) {
+			continue
+		}
+		target, _, _ := strings.Cut(dest, "#")
+		target, _, _ = strings.Cut(target, "?")
+		if unescaped, err := url.PathUnescape(target); err == nil {
+			target = unescaped
+		}
+		ext := filepath.Ext(target)
+		if ext == "" || strings.HasSuffix(target, "/") || strings.EqualFold(ext, ".md") {
+			continue
+		}
+
+		asset := filepath.Join(filepath.Dir(path), filepath.FromSlash(target))
+		if !seen[asset] {
+			seen[asset] = true
+			assets = append(assets, domain.AssetRef{Path: asset, Position: link.Position})
+		}
+	}
+	return assets
+}
 
-	return FindIndexFiles(osFS, rootPath, categories)
+// ListDocuments finds all markdown pages in the specified root path.
+func (r *DocumentReader) ListDocuments(rootPath string, categories domain.CategorySet) ([]string, error) {
+	return FindDocuments(r.fs, rootPath, categories)
 }
@@ -0,0 +1,288 @@
+package filesystem
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/parser"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// countingParser counts the parses a document read makes.
+type countingParser struct {
+	ports.MarkdownParser
+	parses int
+}
+
+func (c *countingParser) Parse(markdown string) ports.ParsedMarkdown {
+	c.parses++
+	return c.MarkdownParser.Parse(markdown)
+}
+
+func TestReadDocument_SharesOneParse(t *testing.T) {
+	content := strings.Join([]string{
+		"---",
+		"title: Tables",
+		"---",
+		"# Tables",
+		"",
+		"Intro.",
+		"",
+		"## Real",
+		"",
+		"| Name | Value |",
+		"| ---- | ----- |",
+		"| a    | `1`   |",
+		"",
+		"```markdown",
+		"## Not a section",
+		"",
+		"| Not | a table |",
+		"```",
+	}, "\n")
+	fs := NewMemoryFileSystem()
+	fs.WriteFile("/docs/patterns/index.md", []byte(content), 0644)
+
+	markdown := &countingParser{MarkdownParser: parser.NewMarkdownParser()}
+	reader := NewDocumentReader(fs, parser.NewFrontmatterParser(), markdown, parser.NewSectionParser(),
+		parser.NewContentExtractor(), NewSnippetResolver(fs, []string{"/docs"}), discoveryCategories)
+
+	doc, err := reader.ReadDocument("/docs/patterns/index.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if markdown.parses != 1 {
+		t.Errorf("parsed %d times, want once", markdown.parses)
+	}
+	if doc.Introduction != "Intro." {
+		t.Errorf("introduction = %q, want %q", doc.Introduction, "Intro.")
+	}
+	if len(doc.Sections) != 1 || len(doc.Sections[0].SubSections) != 1 || doc.Sections[0].SubSections[0].Title != "Real" {
+		t.Errorf("sections = %+v, want only Tables > Real", doc.Sections)
+	}
+	if len(doc.CodeBlocks) != 1 || doc.CodeBlocks[0].Language != "markdown" {
+		t.Errorf("code blocks = %+v, want the markdown fence", doc.CodeBlocks)
+	}
+	wantTable := [][]string{{"a", "`1`"}}
+	if len(doc.Tables) != 1 || !reflect.DeepEqual(doc.Tables[0].Headers, []string{"Name", "Value"}) || !reflect.DeepEqual(doc.Tables[0].Rows, wantTable) {
+		t.Errorf("tables = %+v, want only the real table", doc.Tables)
+	}
+}
+
+func TestReadDocument_Positions(t *testing.T) {
+	content := strings.Join([]string{
+		"---",
+		"title: Positions",
+		"---",
+		"# Positions",
+		"",
+		"Intro — [a link](other.md) and ![a diagram](flow.png).",
+		"",
+		"## Setup",
+		"",
+		"--8<-- \"shared.md\"",
+		"",
+		"  ```yaml",
+		"  on: push",
+		"  ```",
+		"",
+		"| Name | Value |",
+		"| ---- | ----- |",
+		"| a    | b     |",
+		"",
+		"!!! note \"Heads up\"",
+		"",
+		"    Body.",
+		"",
+		"```mermaid",
+		"graph TD",
+		"```",
+	}, "\n")
+	fs := NewMemoryFileSystem()
+	fs.WriteFile("/docs/patterns/index.md", []byte(content), 0644)
+	fs.WriteFile("/docs/snippets/shared.md", []byte("Some [shared](x.md) text."), 0644)
+
+	reader := NewDocumentReader(fs, parser.NewFrontmatterParser(), parser.NewMarkdownParser(), parser.NewSectionParser(),
+		parser.NewContentExtractor(), NewSnippetResolver(fs, []string{"/docs/snippets"}), discoveryCategories)
+
+	doc, err := reader.ReadDocument("/docs/patterns/index.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Lines count from the top of the file, frontmatter included, and
+	// columns in characters. Anything a snippet pulled in is placed at its
+	// include.
+	at := func(line, column int) domain.Position { return domain.Position{Line: line, Column: column} }
+	if len(doc.Sections) != 1 || len(doc.Sections[0].SubSections) != 1 {
+		t.Fatalf("sections = %+v, want Positions > Setup", doc.Sections)
+	}
+	if s := doc.Sections[0]; s.Position != at(4, 1) || s.EndLine != 26 {
+		t.Errorf("section %q at %+v to line %d, want 4:1 to line 26", s.Title, s.Position, s.EndLine)
+	}
+	if s := doc.Sections[0].SubSections[0]; s.Position != at(8, 1) || s.EndLine != 26 {
+		t.Errorf("section %q at %+v to line %d, want 8:1 to line 26", s.Title, s.Position, s.EndLine)
+	}
+	if len(doc.CodeBlocks) != 2 || doc.CodeBlocks[0].Position != at(12, 3) || doc.CodeBlocks[1].Position != at(24, 1) {
+		t.Errorf("code blocks = %+v, want them at 12:3 and 24:1", doc.CodeBlocks)
+	}
+	if len(doc.Mermaid) != 1 || doc.Mermaid[0].Position != at(24, 1) {
+		t.Errorf("mermaid = %+v, want it at 24:1", doc.Mermaid)
+	}
+	if len(doc.Tables) != 1 || doc.Tables[0].Position != at(16, 1) {
+		t.Errorf("tables = %+v, want it at 16:1", doc.Tables)
+	}
+	if len(doc.Admonitions) != 1 || doc.Admonitions[0].Position != at(20, 1) {
+		t.Errorf("admonitions = %+v, want it at 20:1", doc.Admonitions)
+	}
+
+	wantLinks := []domain.Link{
+		{Dest: "other.md", Position: at(6, 9)},
+		{Dest: "flow.png", Position: at(6, 32)},
+		{Dest: "x.md", Position: at(10, 1)},
+	}
+	if !reflect.DeepEqual(doc.Links, wantLinks) {
+		t.Errorf("links = %+v, want %+v", doc.Links, wantLinks)
+	}
+	wantAssets := []domain.AssetRef{{Path: "/docs/patterns/flow.png", Position: at(6, 32)}}
+	if !reflect.DeepEqual(doc.Assets, wantAssets) {
+		t.Errorf("assets = %+v, want %+v", doc.Assets, wantAssets)
+	}
+}
+
+func TestReadDocument_SectionTree(t *testing.T) {
+	content := strings.Join([]string{
+		"# Patterns { #top }",
+		"",
+		"## Use `skillgen.yaml` with [links](x.md) &amp; \\*escapes\\*",
+		"",
+		"### Deep",
+		"",
+		"#### Deeper",
+		"",
+		"Deepest text.",
+		"",
+		"## Setup",
+		"",
+		"#### Skipped level",
+		"",
+		"## Setup",
+		"",
+		"## Custom { #setup_1 .wide }",
+	}, "\n")
+	fs := NewMemoryFileSystem()
+	fs.WriteFile("/docs/patterns/index.md", []byte(content), 0644)
+
+	reader := NewDocumentReader(fs, parser.NewFrontmatterParser(), parser.NewMarkdownParser(), parser.NewSectionParser(),
+		parser.NewContentExtractor(), NewSnippetResolver(fs, []string{"/docs"}), discoveryCategories)
+
+	doc, err := reader.ReadDocument("/docs/patterns/index.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Each heading holds every deeper one after it, and has the id MkDocs
+	// gives it: its own, or one made from its text that steers clear of
+	// those set explicitly.
+	type node struct {
+		title, id string
+		children  []node
+	}
+	var tree func(sections []domain.Section) []node
+	tree = func(sections []domain.Section) []node {
+		var nodes []node
+		for _, s := range sections {
+			nodes = append(nodes, node{s.Title, s.ID, tree(s.SubSections)})
+		}
+		return nodes
+	}
+	want := []node{{"Patterns", "top", []node{
+		{"Use skillgen.yaml with links & *escapes*", "use-skillgenyaml-with-links-escapes", []node{
+			{"Deep", "deep", []node{
+				{"Deeper", "deeper", nil},
+			}},
+		}},
+		{"Setup", "setup", []node{
+			{"Skipped level", "skipped-level", nil},
+		}},
+		{"Setup", "setup_2", nil},
+		{"Custom", "setup_1", nil},
+	}}}
+	if got := tree(doc.Sections); !reflect.DeepEqual(got, want) {
+		t.Errorf("sections = %+v, want %+v", got, want)
+	}
+
+	deeper := doc.Sections[0].SubSections[0].SubSections[0].SubSections[0]
+	if deeper.Content != "Deepest text." || deeper.Position != (domain.Position{Line: 7, Column: 1}) || deeper.EndLine != 9 {
+		t.Errorf("deeper = %+v, want its text, from line 7 to 9", deeper)
+	}
+}
+
+func TestLocalAssets(t *testing.T) {
+	dests := []string{
+		"diagrams/flow.png#dark",
+		"../assets/policy.yaml?raw=1",
+		"my%20diagram.svg",
+		"diagrams/flow.png",
+		"other/page.md#section",
+		"other/",
+		"other",
+		"#top",
+		"/assets/site.png",
+		"https://example.com/x.png",
+		"mailto:team@example.com",
+		"",
+	}
+
+	var links []domain.Link
+	for i, dest := range dests {
+		links = append(links, domain.Link{Dest: dest, Position: domain.Position{Line: i + 1, Column: 3}})
+	}
+
+	got := localAssets("/docs/patterns/a/index.md", links)
+	want := []domain.AssetRef{
+		{Path: "/docs/patterns/a/diagrams/flow.png", Position: domain.Position{Line: 1, Column: 3}},
+		{Path: "/docs/patterns/assets/policy.yaml", Position: domain.Position{Line: 2, Column: 3}},
+		{Path: "/docs/patterns/a/my diagram.svg", Position: domain.Position{Line: 3, Column: 3}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("localAssets = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadDocument_FindsAssetsInsideTabsAndAdmonitions(t *testing.T) {
+	content := strings.Join([]string{
+		"---",
+		"title: Assets",
+		"---",
+		"# Assets",
+		"",
+		"=== \"Argo\"",
+		"",
+		"    ![flow](tab.png)",
+		"",
+		"!!! note",
+		"    Download [the policy](policy.yaml).",
+	}, "\n")
+	fs := NewMemoryFileSystem()
+	fs.WriteFile("/docs/patterns/index.md", []byte(content), 0644)
+
+	reader := NewDocumentReader(fs, parser.NewFrontmatterParser(), parser.NewMarkdownParser(), parser.NewSectionParser(),
+		parser.NewContentExtractor(), NewSnippetResolver(fs, []string{"/docs"}), discoveryCategories)
+
+	doc, err := reader.ReadDocument("/docs/patterns/index.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.AssetRef{
+		{Path: "/docs/patterns/tab.png", Position: domain.Position{Line: 8, Column: 5}},
+		{Path: "/docs/patterns/policy.yaml", Position: domain.Position{Line: 11, Column: 14}},
+	}
+	if !reflect.DeepEqual(doc.Assets, want) {
+		t.Errorf("assets = %+v, want %+v", doc.Assets, want)
+	}
+}
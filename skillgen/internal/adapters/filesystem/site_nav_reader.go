@@ -0,0 +1,124 @@
+package filesystem
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// SiteNavReader implements ports.SiteNavReader for MkDocs' mkdocs.yml.
+type SiteNavReader struct {
+	fs ports.FileSystem
+}
+
+// NewSiteNavReader creates a new filesystem-based mkdocs.yml nav reader.
+func NewSiteNavReader(fs ports.FileSystem) *SiteNavReader {
+	return &SiteNavReader{fs: fs}
+}
+
+// ReadSiteNav reads the nav: tree of the mkdocs.yml at path. The file is
+// decoded as a bare YAML node tree, so the custom tags mkdocs.yml files
+// commonly use elsewhere (!ENV, !!python/name:...) need no support.
+//
+// A nav entry is either a bare page path, "Title: page.md", or
+// "Section: [entries]". A bare index.md heading a section's entries is the
+// section's own page and takes its title, as Material's navigation.indexes
+// shows it.
+func (r *SiteNavReader) ReadSiteNav(path string) (*domain.SiteNav, error) {
+	content, err := r.fs.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	nav := findNav(&root)
+	if nav == nil {
+		return nil, fmt.Errorf("%s has no nav: tree", path)
+	}
+	if nav.Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("nav in %s is not a list", path)
+	}
+
+	var pages []domain.NavPage
+	if err := collectNavPages(nav, "", &pages); err != nil {
+		return nil, fmt.Errorf("invalid nav in %s: %w", path, err)
+	}
+
+	return domain.NewSiteNav(pages), nil
+}
+
+// findNav returns the value of the top-level nav: key, or nil.
+func findNav(root *yaml.Node) *yaml.Node {
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 {
+		return nil
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value == "nav" {
+			return doc.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// collectNavPages appends the pages in the nav list entries, depth-first.
+// section is the title of the section entries belongs to, if any.
+func collectNavPages(entries *yaml.Node, section string, pages *[]domain.NavPage) error {
+	for i, entry := range entries.Content {
+		switch entry.Kind {
+		case yaml.ScalarNode:
+			title := ""
+			if i == 0 && path.Base(entry.Value) == "index.md" {
+				title = section
+			}
+			addNavPage(entry, title, pages)
+
+		case yaml.MappingNode:
+			if len(entry.Content) != 2 {
+				return fmt.Errorf("line %d: a nav entry must have exactly one title", entry.Line)
+			}
+			title, value := entry.Content[0].Value, entry.Content[1]
+			switch value.Kind {
+			case yaml.ScalarNode:
+				addNavPage(value, title, pages)
+			case yaml.SequenceNode:
+				if err := collectNavPages(value, title, pages); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("line %d: nav entry %q is neither a page nor a section", value.Line, title)
+			}
+
+		default:
+			return fmt.Errorf("line %d: nav entries must be pages or sections", entry.Line)
+		}
+	}
+	return nil
+}
+
+// addNavPage appends the page a nav value names, unless it is an external
+// link or a custom-tagged value such as !include that names no page.
+func addNavPage(value *yaml.Node, title string, pages *[]domain.NavPage) {
+	target := value.Value
+	if value.ShortTag() != "!!str" || strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") {
+		return
+	}
+	if !strings.EqualFold(path.Ext(target), ".md") {
+		return
+	}
+
+	target = strings.TrimPrefix(path.Clean("/"+target), "/")
+	*pages = append(*pages, domain.NavPage{Path: target, Title: title, Line: value.Line})
+}
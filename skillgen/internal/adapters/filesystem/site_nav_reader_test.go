@@ -0,0 +1,86 @@
+package filesystem
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+const testMkDocsConfig = `site_name: AEL
+theme:
+  name: material
+markdown_extensions:
+  - pymdownx.emoji:
+      emoji_generator: !!python/name:material.extensions.emoji.to_svg
+extra:
+  analytics:
+    property: !ENV GOOGLE_ANALYTICS_KEY
+nav:
+  - Home: index.md
+  - Patterns:
+      - patterns/index.md
+      - Idempotency:
+          - patterns/idempotency/index.md
+          - Checkpoints: patterns/idempotency/checkpoints.md
+          - patterns/idempotency/retries.md
+          - patterns/idempotency/legacy/index.md
+      - Hub and Spoke: ./patterns/architecture/hub-and-spoke/index.md
+  - Blog: https://example.com/blog/
+  - Changelog: !include ../CHANGELOG.md
+`
+
+func TestSiteNavReader_ReadSiteNav(t *testing.T) {
+	fs := NewMockFileSystem()
+	fs.AddFile("mkdocs.yml", []byte(testMkDocsConfig))
+
+	nav, err := NewSiteNavReader(fs).ReadSiteNav("mkdocs.yml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.NavPage{
+		{Path: "index.md", Title: "Home", Line: 11},
+		{Path: "patterns/index.md", Title: "Patterns", Line: 13},
+		{Path: "patterns/idempotency/index.md", Title: "Idempotency", Line: 15},
+		{Path: "patterns/idempotency/checkpoints.md", Title: "Checkpoints", Line: 16},
+		{Path: "patterns/idempotency/retries.md", Line: 17},
+		{Path: "patterns/idempotency/legacy/index.md", Line: 18},
+		{Path: "patterns/architecture/hub-and-spoke/index.md", Title: "Hub and Spoke", Line: 19},
+	}
+	if !reflect.DeepEqual(nav.Pages, want) {
+		t.Errorf("expected %+v, got %+v", want, nav.Pages)
+	}
+
+	if page, position, ok := nav.Lookup("patterns/idempotency/checkpoints.md"); !ok || position != 3 || page.Title != "Checkpoints" {
+		t.Errorf("expected checkpoints.md at position 3, got %+v, %d, %v", page, position, ok)
+	}
+	if got := nav.Under("patterns/idempotency"); len(got) != 4 {
+		t.Errorf("expected the four idempotency pages, got %+v", got)
+	}
+}
+
+func TestSiteNavReader_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		errContains string
+	}{
+		{"no nav", "site_name: AEL\n", "no nav"},
+		{"nav not a list", "nav: index.md\n", "not a list"},
+		{"entry with two titles", "nav:\n  - A: a.md\n    B: b.md\n", "exactly one title"},
+		{"invalid yaml", "nav: [\n", "failed to parse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := NewMockFileSystem()
+			fs.AddFile("mkdocs.yml", []byte(tt.content))
+			_, err := NewSiteNavReader(fs).ReadSiteNav("mkdocs.yml")
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("expected an error containing %q, got %v", tt.errContains, err)
+			}
+		})
+	}
+}
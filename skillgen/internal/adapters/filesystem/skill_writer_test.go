@@ -0,0 +1,108 @@
+package filesystem
+
+import (
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+// stubRenderer renders a skill's name, so output only changes when the
+// test changes the skill.
+type stubRenderer struct{}
+
+func (stubRenderer) RenderSkill(skill *domain.Skill) (string, error) {
+	return "# " + skill.Metadata.Title + "\n", nil
+}
+
+func (stubRenderer) RenderReference(skill *domain.Skill) (string, error) {
+	return "# " + skill.Metadata.Title + " reference\n", nil
+}
+
+func (stubRenderer) RenderReadme(data *domain.ReadmeData) (string, error) {
+	return "", nil
+}
+
+// countingFileSystem records which paths were written.
+type countingFileSystem struct {
+	*MemoryFileSystem
+	writes []string
+}
+
+func (c *countingFileSystem) WriteFile(path string, data []byte, perm int) error {
+	c.writes = append(c.writes, path)
+	return c.MemoryFileSystem.WriteFile(path, data, perm)
+}
+
+func testSkill() *domain.Skill {
+	return &domain.Skill{
+		Metadata: domain.SkillMetadata{Name: "patterns", Title: "Patterns", Category: "patterns"},
+		LibraryFiles: []domain.LibraryFile{
+			{RelPath: "index.md", Content: "root"},
+			{RelPath: "architecture/index.md", Content: "architecture"},
+			{RelPath: "architecture/hub-and-spoke/index.md", Content: "hub"},
+		},
+		Assets: []domain.LibraryAsset{
+			{RelPath: "architecture/flow.png", Content: []byte("png")},
+		},
+	}
+}
+
+func TestSkillWriter_WriteSkill(t *testing.T) {
+	fs := &countingFileSystem{MemoryFileSystem: NewMemoryFileSystem()}
+	fs.MemoryFileSystem.WriteFile("out/patterns/skills/old-doc-skill/SKILL.md", []byte("stale sibling"), 0644)
+	writer := NewSkillWriter(fs, stubRenderer{})
+
+	if err := writer.WriteSkill(testSkill(), "out"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{
+		"out/patterns/skills/patterns/SKILL.md":                                    "# Patterns\n",
+		"out/patterns/skills/patterns/reference.md":                                "# Patterns reference\n",
+		"out/patterns/skills/patterns/library/index.md":                            "root",
+		"out/patterns/skills/patterns/library/architecture/index.md":               "architecture",
+		"out/patterns/skills/patterns/library/architecture/hub-and-spoke/index.md": "hub",
+		"out/patterns/skills/patterns/library/architecture/flow.png":               "png",
+	}
+	files := fs.Files()
+	if len(files) != len(want) {
+		t.Errorf("wrote %d files, want %d: %v", len(files), len(want), fs.writes)
+	}
+	for path, content := range want {
+		if string(files[path]) != content {
+			t.Errorf("%s = %q, want %q", path, files[path], content)
+		}
+	}
+}
+
+func TestSkillWriter_RewritesOnlyChangedFiles(t *testing.T) {
+	fs := &countingFileSystem{MemoryFileSystem: NewMemoryFileSystem()}
+	writer := NewSkillWriter(fs, stubRenderer{})
+
+	if err := writer.WriteSkill(testSkill(), "out"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	skill := testSkill()
+	skill.LibraryFiles = []domain.LibraryFile{
+		{RelPath: "index.md", Content: "root, edited"},
+		{RelPath: "architecture/index.md", Content: "architecture"},
+	}
+	fs.writes = nil
+	if err := writer.WriteSkill(skill, "out"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(fs.writes) != 1 || fs.writes[0] != "out/patterns/skills/patterns/library/index.md" {
+		t.Errorf("expected only the edited library file to be rewritten, got %v", fs.writes)
+	}
+	if fs.Exists("out/patterns/skills/patterns/library/architecture/hub-and-spoke/index.md") {
+		t.Error("expected the dropped library file to be removed")
+	}
+	if fs.Exists("out/patterns/skills/patterns/library/architecture/hub-and-spoke") {
+		t.Error("expected the emptied directory to be removed")
+	}
+	if !fs.Exists("out/patterns/skills/patterns/library/architecture/index.md") {
+		t.Error("expected the unchanged library file to remain")
+	}
+}
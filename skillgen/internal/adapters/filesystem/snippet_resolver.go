@@ -0,0 +1,184 @@
+package filesystem
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// snippetInline matches a single-file include, with its target quoted:
+//
+//	--8<-- "examples/ci.yaml:setup"
+//
+// Group 1 is the indentation, 2 any escaping ";", and 3 the quoted target.
+var snippetInline = regexp.MustCompile(`^([ \t]*)(;*)-+8<-+[ \t]+("(?:\\"|[^"])+"|'(?:\\'|[^'])+')[ \t]*$`)
+
+// snippetBlock matches the line that opens or closes a block include, with
+// one target per line between:
+//
+//	--8<--
+//	examples/ci.yaml
+//	examples/argo.yaml
+//	--8<--
+var snippetBlock = regexp.MustCompile(`^([ \t]*)(;*)-+8<-+[ \t]*$`)
+
+// SnippetResolver implements ports.SnippetResolver, reading snippet files
+// from a set of base directories as pymdownx.snippets does.
+type SnippetResolver struct {
+	fs        ports.FileSystem
+	basePaths []string
+}
+
+// NewSnippetResolver creates a snippet resolver that looks each target up
+// under basePaths, in order, and takes the first file found.
+func NewSnippetResolver(fs ports.FileSystem, basePaths []string) *SnippetResolver {
+	return &SnippetResolver{fs: fs, basePaths: basePaths}
+}
+
+// Resolve expands every include in content. Includes are expanded inside
+// fenced code blocks too, since that is where shared examples are usually
+// pulled in; an escaped include (";--8<--") is kept, unescaped, as text.
+// Included lines take the include's indentation.
+func (r *SnippetResolver) Resolve(content string) (string, []domain.Snippet, domain.SourceMap) {
+	var snippets []domain.Snippet
+	var sources domain.SourceMap
+	lines := r.expand(strings.Split(content, "\n"), nil, 0, &snippets, &sources)
+	return strings.Join(lines, "\n"), snippets, sources
+}
+
+// expand replaces the includes in lines. stack holds the files being
+// included, so a snippet that includes itself stops; line is the document
+// line of the outermost include, or 0 while expanding the document. While
+// expanding the document, sources gets where each line of the result came
+// from.
+func (r *SnippetResolver) expand(lines []string, stack []string, line int, snippets *[]domain.Snippet, sources *domain.SourceMap) []string {
+	var result []string
+	add := func(at int, included bool, expanded ...string) {
+		result = append(result, expanded...)
+		if line == 0 {
+			for range expanded {
+				*sources = append(*sources, domain.SourceLine{Line: at, Included: included})
+			}
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		at := line
+		if at == 0 {
+			at = i + 1
+		}
+
+		if match := snippetInline.FindStringSubmatch(lines[i]); match != nil {
+			if match[2] != "" {
+				add(at, false, strings.Replace(lines[i], ";", "", 1))
+				continue
+			}
+			target := unquoteSnippetTarget(match[3])
+			add(at, true, r.include(target, match[1], stack, at, snippets)...)
+			continue
+		}
+
+		match := snippetBlock.FindStringSubmatch(lines[i])
+		if match == nil {
+			add(at, false, lines[i])
+			continue
+		}
+		if match[2] != "" {
+			add(at, false, strings.Replace(lines[i], ";", "", 1))
+			continue
+		}
+		end := i + 1
+		for end < len(lines) && !snippetBlock.MatchString(lines[end]) {
+			end++
+		}
+		if end == len(lines) {
+			// An unclosed block is just text.
+			add(at, false, lines[i])
+			continue
+		}
+		for j := i + 1; j < end; j++ {
+			if line == 0 {
+				at = j + 1
+			}
+			target := strings.TrimSpace(lines[j])
+			if target == "" || strings.HasPrefix(target, ";") {
+				continue
+			}
+			add(at, true, r.include(target, match[1], stack, at, snippets)...)
+		}
+		i = end
+	}
+
+	return result
+}
+
+// include returns the lines target names, expanded and indented, and
+// records the include in snippets.
+func (r *SnippetResolver) include(target, indent string, stack []string, line int, snippets *[]domain.Snippet) []string {
+	t := domain.ParseSnippetTarget(target)
+	snippet := domain.Snippet{Target: target, Line: line}
+	fail := func(format string, args ...any) []string {
+		snippet.Problem = fmt.Sprintf("snippet %q ", target) + fmt.Sprintf(format, args...)
+		*snippets = append(*snippets, snippet)
+		return nil
+	}
+
+	if strings.Contains(t.Path, "://") {
+		return fail("is a URL; remote snippets are not fetched")
+	}
+	path, ok := r.locate(t.Path)
+	if !ok {
+		return fail("not found under %s", strings.Join(r.basePaths, ", "))
+	}
+	snippet.Path = path
+	for _, including := range stack {
+		if including == path {
+			return fail("includes itself")
+		}
+	}
+
+	content, err := r.fs.ReadFile(path)
+	if err != nil {
+		return fail("could not be read: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	selected, err := t.Select(lines)
+	if err != nil {
+		return fail("%v", err)
+	}
+	*snippets = append(*snippets, snippet)
+
+	expanded := r.expand(selected, append(stack[:len(stack):len(stack)], path), line, snippets, nil)
+	for i, l := range expanded {
+		if strings.TrimSpace(l) != "" {
+			expanded[i] = indent + l
+		}
+	}
+	return expanded
+}
+
+// locate finds the snippet file at rel under the first base path that has
+// it. A target that climbs out of a base path is not looked up there.
+func (r *SnippetResolver) locate(rel string) (string, bool) {
+	for _, base := range r.basePaths {
+		path := filepath.Join(base, filepath.FromSlash(rel))
+		if inside, err := filepath.Rel(base, path); err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if r.fs.Exists(path) && !r.fs.IsDir(path) {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+// unquoteSnippetTarget strips a target's quotes and unescapes any quote
+// inside it.
+func unquoteSnippetTarget(quoted string) string {
+	quote := quoted[:1]
+	return strings.ReplaceAll(quoted[1:len(quoted)-1], `\`+quote, quote)
+}
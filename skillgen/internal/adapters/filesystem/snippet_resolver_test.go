@@ -0,0 +1,111 @@
+package filesystem
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+func TestSnippetResolverExpandsIncludes(t *testing.T) {
+	fs := NewMockFileSystem()
+	fs.AddFile("/docs/snippets/ci.yaml", []byte("on: push\n# --8<-- [start:job]\njobs:\n  build: {}\n# --8<-- [end:job]\n"))
+	fs.AddFile("/shared/note.md", []byte("Shared.\n--8<-- \"ci.yaml:1:1\"\n"))
+
+	resolver := NewSnippetResolver(fs, []string{"/docs/snippets", "/shared"})
+	content, snippets, lines := resolver.Resolve(strings.Join([]string{
+		"# Page",
+		"",
+		"```yaml",
+		"--8<-- \"ci.yaml:job\"",
+		"```",
+		"",
+		"- Item",
+		"",
+		"    --8<--",
+		"    note.md",
+		"    ; skipped.md",
+		"    --8<--",
+		"",
+		";--8<-- \"literal.md\"",
+		"--8<-- 'missing.md'",
+	}, "\n"))
+
+	want := strings.Join([]string{
+		"# Page",
+		"",
+		"```yaml",
+		"jobs:",
+		"  build: {}",
+		"```",
+		"",
+		"- Item",
+		"",
+		"    Shared.",
+		"    on: push",
+		"",
+		"--8<-- \"literal.md\"",
+	}, "\n")
+	if content != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+
+	wantSnippets := []domain.Snippet{
+		{Target: "ci.yaml:job", Path: "/docs/snippets/ci.yaml", Line: 4},
+		{Target: "note.md", Path: "/shared/note.md", Line: 10},
+		{Target: "ci.yaml:1:1", Path: "/docs/snippets/ci.yaml", Line: 10},
+		{Target: "missing.md", Line: 15, Problem: `snippet "missing.md" not found under /docs/snippets, /shared`},
+	}
+	if len(snippets) != len(wantSnippets) {
+		t.Fatalf("snippets = %+v, want %+v", snippets, wantSnippets)
+	}
+	for i, want := range wantSnippets {
+		if snippets[i] != want {
+			t.Errorf("snippets[%d] = %+v, want %+v", i, snippets[i], want)
+		}
+	}
+
+	// Included lines map to the include, nested ones to the outermost.
+	wantLines := domain.SourceMap{
+		{Line: 1}, {Line: 2}, {Line: 3},
+		{Line: 4, Included: true}, {Line: 4, Included: true},
+		{Line: 5}, {Line: 6}, {Line: 7}, {Line: 8},
+		{Line: 10, Included: true}, {Line: 10, Included: true},
+		{Line: 13}, {Line: 14},
+	}
+	if !reflect.DeepEqual(lines, wantLines) {
+		t.Errorf("lines = %+v, want %+v", lines, wantLines)
+	}
+}
+
+func TestSnippetResolverReportsProblems(t *testing.T) {
+	fs := NewMockFileSystem()
+	fs.AddFile("/docs/loop.md", []byte("--8<-- \"loop.md\"\n"))
+	fs.AddFile("/docs/ci.yaml", []byte("on: push\n"))
+	fs.AddFile("/outside.md", []byte("secret\n"))
+
+	resolver := NewSnippetResolver(fs, []string{"/docs"})
+	_, snippets, _ := resolver.Resolve("--8<-- \"loop.md\"\n--8<-- \"ci.yaml:setup\"\n--8<-- \"../outside.md\"\n--8<-- \"https://example.com/a.md\"")
+
+	want := []string{
+		`snippet "loop.md" includes itself`,
+		`snippet "ci.yaml:setup" section "setup" not found`,
+		`snippet "../outside.md" not found under /docs`,
+		`snippet "https://example.com/a.md" is a URL; remote snippets are not fetched`,
+	}
+	var problems []string
+	for _, s := range snippets {
+		if s.Problem != "" {
+			problems = append(problems, s.Problem)
+		}
+	}
+	if strings.Join(problems, "\n") != strings.Join(want, "\n") {
+		t.Errorf("problems = %q, want %q", problems, want)
+	}
+}
+
+func TestSnippetResolverSatisfiesPortInterface(t *testing.T) {
+	var _ ports.SnippetResolver = NewSnippetResolver(NewMockFileSystem(), nil)
+}
@@ -1,8 +1,10 @@
 package filesystem
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -25,13 +27,16 @@ func NewSkillWriter(fs ports.FileSystem, renderer ports.TemplateRenderer) *Skill
 	}
 }
 
-// WriteSkill writes the hub skill's SKILL.md and reference.md to the output
-// directory. It first removes any stale sibling skill directories left over
-// from a previous generation (e.g. the old one-skill-per-doc layout), and
-// then wipes the hub's own directory before recreating it — a category's
-// root doc can share its name with the new hub (e.g. "patterns"), in which
-// case the old per-doc skill's leftover examples.md/scripts/ would
-// otherwise survive as a same-named "sibling of itself".
+// WriteSkill writes the hub skill's SKILL.md, reference.md, and library/
+// tree to the output directory. It first removes any stale sibling skill
+// directories left over from a previous generation (e.g. the old
+// one-skill-per-doc layout). Within the hub's own directory only files
+// whose content changed are rewritten, and files no longer generated are
+// deleted — a category's root doc can share its name with the hub (e.g.
+// "patterns"), in which case an old per-doc skill's leftover
+// examples.md/scripts/ would otherwise survive as a same-named "sibling of
+// itself". Unchanged files keep their mtimes, so a regeneration only
+// touches what actually changed.
 func (w *SkillWriter) WriteSkill(skill *domain.Skill, outputDir string) error {
 	skillsDir := filepath.Join(outputDir, skill.Metadata.Category, "skills")
 	skillDir := filepath.Join(skillsDir, skill.Metadata.Name)
@@ -40,8 +45,14 @@ func (w *SkillWriter) WriteSkill(skill *domain.Skill, outputDir string) error {
 		return fmt.Errorf("failed to remove stale skill directories in %s: %w", skillsDir, err)
 	}
 
-	if err := w.fs.RemoveAll(skillDir); err != nil {
-		return fmt.Errorf("failed to clear skill directory %s: %w", skillDir, err)
+	files, err := w.render(skill)
+	if err != nil {
+		return err
+	}
+
+	existing, err := w.listFiles(skillDir)
+	if err != nil {
+		return fmt.Errorf("failed to list skill directory %s: %w", skillDir, err)
 	}
 
 	// Create skill directory
@@ -49,36 +60,111 @@ func (w *SkillWriter) WriteSkill(skill *domain.Skill, outputDir string) error {
 		return fmt.Errorf("failed to create skill directory %s: %w", skillDir, err)
 	}
 
-	// Write SKILL.md: the lean, scannable index
+	// WriteFile creates parent directories as needed, so no separate
+	// MkdirAll per library file is required.
+	relPaths := make([]string, 0, len(files))
+	for relPath := range files {
+		relPaths = append(relPaths, relPath)
+	}
+	sort.Strings(relPaths)
+
+	for _, relPath := range relPaths {
+		path := filepath.Join(skillDir, filepath.FromSlash(relPath))
+		if current, err := w.fs.ReadFile(path); err == nil && bytes.Equal(current, files[relPath]) {
+			continue
+		}
+		if err := w.fs.WriteFile(path, files[relPath], 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", relPath, err)
+		}
+	}
+
+	for _, path := range existing {
+		rel, err := filepath.Rel(skillDir, path)
+		if err != nil {
+			return err
+		}
+		if _, keep := files[filepath.ToSlash(rel)]; keep {
+			continue
+		}
+		if err := w.fs.RemoveAll(path); err != nil {
+			return fmt.Errorf("failed to remove stale file %s: %w", path, err)
+		}
+	}
+
+	return w.removeEmptyDirs(skillDir)
+}
+
+// render renders every file of the hub skill, keyed by slash-separated path
+// relative to the skill directory: SKILL.md, the lean, scannable index;
+// reference.md, the full offline depth behind it; and library/, every
+// source doc verbatim, mirroring the docs tree, with the assets they link
+// to beside them.
+func (w *SkillWriter) render(skill *domain.Skill) (map[string][]byte, error) {
+	files := make(map[string][]byte, len(skill.LibraryFiles)+len(skill.Assets)+2)
+
 	skillContent, err := w.renderer.RenderSkill(skill)
 	if err != nil {
-		return fmt.Errorf("failed to render SKILL.md for %s: %w", skill.Metadata.Name, err)
+		return nil, fmt.Errorf("failed to render SKILL.md for %s: %w", skill.Metadata.Name, err)
 	}
+	files["SKILL.md"] = []byte(skillContent)
 
-	skillPath := filepath.Join(skillDir, "SKILL.md")
-	if err := w.fs.WriteFile(skillPath, []byte(skillContent), 0644); err != nil {
-		return fmt.Errorf("failed to write SKILL.md: %w", err)
+	referenceContent, err := w.renderer.RenderReference(skill)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render reference.md for %s: %w", skill.Metadata.Name, err)
 	}
+	files["reference.md"] = []byte(referenceContent)
 
-	// Write reference.md: the full offline depth behind it
-	referenceContent, err := w.renderer.RenderReference(skill)
+	for _, lf := range skill.LibraryFiles {
+		files["library/"+lf.RelPath] = []byte(lf.Content)
+	}
+	for _, asset := range skill.Assets {
+		files["library/"+asset.RelPath] = asset.Content
+	}
+
+	return files, nil
+}
+
+// listFiles returns every file under dir, recursively. A missing dir has
+// no files.
+func (w *SkillWriter) listFiles(dir string) ([]string, error) {
+	if !w.fs.IsDir(dir) {
+		return nil, nil
+	}
+
+	var files []string
+	err := w.fs.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		files = append(files, path)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to render reference.md for %s: %w", skill.Metadata.Name, err)
+		return nil, err
 	}
 
-	referencePath := filepath.Join(skillDir, "reference.md")
-	if err := w.fs.WriteFile(referencePath, []byte(referenceContent), 0644); err != nil {
-		return fmt.Errorf("failed to write reference.md: %w", err)
+	return files, nil
+}
+
+// removeEmptyDirs deletes directories under dir left empty by stale file
+// removal, deepest first.
+func (w *SkillWriter) removeEmptyDirs(dir string) error {
+	entries, err := w.fs.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return err
 	}
 
-	// Write library/: every source doc verbatim, mirroring the docs tree.
-	// WriteFile creates parent directories as needed, so no separate
-	// MkdirAll per file is required.
-	libraryDir := filepath.Join(skillDir, "library")
-	for _, lf := range skill.LibraryFiles {
-		libraryPath := filepath.Join(libraryDir, lf.RelPath)
-		if err := w.fs.WriteFile(libraryPath, []byte(lf.Content), 0644); err != nil {
-			return fmt.Errorf("failed to write library file %s: %w", lf.RelPath, err)
+	for _, entry := range entries {
+		if !w.fs.IsDir(entry) {
+			continue
+		}
+		if err := w.removeEmptyDirs(entry); err != nil {
+			return err
+		}
+		if children, err := w.fs.Glob(filepath.Join(entry, "*")); err == nil && len(children) == 0 {
+			if err := w.fs.RemoveAll(entry); err != nil {
+				return fmt.Errorf("failed to remove empty directory %s: %w", entry, err)
+			}
 		}
 	}
 
@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"sync"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// Recorder implements ports.Logger by holding every entry back until Replay.
+// Work running in parallel logs into its own Recorder, and the recorders are
+// replayed in a fixed order, so the log reads the same as a serial run's.
+type Recorder struct {
+	recording *recording
+	context   []interface{}
+}
+
+// recording is the entry list a Recorder and its With children share.
+type recording struct {
+	mu      sync.Mutex
+	entries []entry
+}
+
+type entry struct {
+	level         ports.LogLevel
+	msg           string
+	context       []interface{}
+	keysAndValues []interface{}
+}
+
+// NewRecorder creates an empty recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{recording: &recording{}}
+}
+
+// Info records an informational message.
+func (r *Recorder) Info(msg string, keysAndValues ...interface{}) {
+	r.record(ports.LogLevelInfo, msg, keysAndValues)
+}
+
+// Warn records a warning message.
+func (r *Recorder) Warn(msg string, keysAndValues ...interface{}) {
+	r.record(ports.LogLevelWarn, msg, keysAndValues)
+}
+
+// Error records an error message.
+func (r *Recorder) Error(msg string, keysAndValues ...interface{}) {
+	r.record(ports.LogLevelError, msg, keysAndValues)
+}
+
+// Debug records a debug message.
+func (r *Recorder) Debug(msg string, keysAndValues ...interface{}) {
+	r.record(ports.LogLevelDebug, msg, keysAndValues)
+}
+
+// With creates a child recorder with additional context fields. Its entries
+// are replayed with the parent's, in the order they were recorded.
+func (r *Recorder) With(keysAndValues ...interface{}) ports.Logger {
+	context := append(append([]interface{}(nil), r.context...), keysAndValues...)
+	return &Recorder{recording: r.recording, context: context}
+}
+
+// Replay logs every recorded entry to target, in order, and empties the
+// recorder. Level filtering is left to target.
+func (r *Recorder) Replay(target ports.Logger) {
+	r.recording.mu.Lock()
+	entries := r.recording.entries
+	r.recording.entries = nil
+	r.recording.mu.Unlock()
+
+	for _, e := range entries {
+		logger := target
+		if len(e.context) > 0 {
+			logger = target.With(e.context...)
+		}
+
+		switch e.level {
+		case ports.LogLevelDebug:
+			logger.Debug(e.msg, e.keysAndValues...)
+		case ports.LogLevelInfo:
+			logger.Info(e.msg, e.keysAndValues...)
+		case ports.LogLevelWarn:
+			logger.Warn(e.msg, e.keysAndValues...)
+		default:
+			logger.Error(e.msg, e.keysAndValues...)
+		}
+	}
+}
+
+func (r *Recorder) record(level ports.LogLevel, msg string, keysAndValues []interface{}) {
+	r.recording.mu.Lock()
+	defer r.recording.mu.Unlock()
+
+	r.recording.entries = append(r.recording.entries, entry{
+		level:         level,
+		msg:           msg,
+		context:       r.context,
+		keysAndValues: keysAndValues,
+	})
+}
+
+// Ensure Recorder implements ports.Logger
+var _ ports.Logger = (*Recorder)(nil)
@@ -3,82 +3,353 @@ package parser
 import (
 	"regexp"
 	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
 )
 
-// AdmonitionConverter implements ports.AdmonitionConverter.
-type AdmonitionConverter struct {
-	// Pattern matches: !!! type "title"
-	pattern *regexp.Regexp
+// admonitionHeader matches the first line of a Material for MkDocs
+// admonition or collapsible details block, at any indentation:
+//
+//	!!! note
+//	!!! tip "Helpful hint"
+//	!!! tip inline end "Side note"
+//	??? warning "Collapsed"
+//	???+ info ""
+//
+// Group 1 is the indentation, 2 the marker, 3 the type and any extra
+// classes, and 4, if present, the quoted title.
+var admonitionHeader = regexp.MustCompile(`^([ \t]*)(!!!|\?\?\?\+?)[ \t]+([\w-]+(?:[ \t]+[\w-]+)*)(?:[ \t]+"(.*)")?[ \t]*$`)
+
+// admonitionIndent is how far an admonition's body is indented past its
+// header.
+const admonitionIndent = 4
+
+// admonition is one parsed admonition block.
+type admonition struct {
+	// Type is the admonition type (note, tip, warning, ...), and Classes
+	// any extra classes after it, such as "inline end".
+	Type    string
+	Classes []string
+
+	// Title is the quoted title. Untitled is set when there is none, in
+	// which case Material shows the type as the title; an explicit ""
+	// title hides the title bar instead.
+	Title    string
+	Untitled bool
+
+	// Collapsible is set for ??? and ???+ details blocks, and Open for
+	// ???+, which starts expanded.
+	Collapsible bool
+	Open        bool
+
+	// Indent is the header's leading whitespace, and Line its index in the
+	// lines it was parsed from.
+	Indent string
+	Line   int
+
+	// Body is the block's content with its indentation removed and
+	// trailing blank lines dropped. Nested admonitions in it are left as
+	// written; parse Body again to reach them.
+	Body []string
 }
 
-// NewAdmonitionConverter creates a new admonition converter.
-func NewAdmonitionConverter() *AdmonitionConverter {
-	// Match admonitions like: !!! abstract "What You'll Learn"
-	pattern := regexp.MustCompile(`(?m)^!!!\s+(\w+)\s+"([^"]+)"\s*$`)
+// displayTitle is the title Material renders: the quoted title, or the
+// capitalized type for an untitled block.
+func (a *admonition) displayTitle() string {
+	if !a.Untitled {
+		return a.Title
+	}
+	return strings.ToUpper(a.Type[:1]) + a.Type[1:]
+}
+
+// admonitionNode is one item of parsed content: a plain line, or a whole
+// admonition block.
+type admonitionNode struct {
+	line       string
+	admonition *admonition
+}
+
+// parseAdmonitions splits lines into plain lines and admonition blocks. An
+// admonition's body is every following line that is blank or indented at
+// least four columns past its header; headers inside fenced code blocks
+// are left alone. Both AdmonitionConverter and
+// ContentExtractor.ExtractAdmonitions read admonitions through here, so
+// they always agree on what one is.
+func parseAdmonitions(lines []string) []admonitionNode {
+	var nodes []admonitionNode
+	var fence string
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if fence != "" {
+			if isFenceClose(line, fence) {
+				fence = ""
+			}
+			nodes = append(nodes, admonitionNode{line: line})
+			continue
+		}
+		if marker := fenceMarker(line); marker != "" {
+			fence = marker
+			nodes = append(nodes, admonitionNode{line: line})
+			continue
+		}
+
+		match := admonitionHeader.FindStringSubmatch(line)
+		if match == nil {
+			nodes = append(nodes, admonitionNode{line: line})
+			continue
+		}
+
+		classes := strings.Fields(match[3])
+		a := &admonition{
+			Type:        classes[0],
+			Classes:     classes[1:],
+			Title:       match[4],
+			Untitled:    !strings.HasSuffix(strings.TrimRight(line, " \t"), `"`),
+			Collapsible: match[2] != "!!!",
+			Open:        match[2] == "???+",
+			Indent:      match[1],
+			Line:        i,
+		}
+
+		var end int
+		a.Body, end = indentedBlock(lines, i+1, indentWidth(match[1])+admonitionIndent)
+
+		nodes = append(nodes, admonitionNode{admonition: a})
+		i = end - 1
+	}
+
+	return nodes
+}
+
+// indentedBlock collects the body of a block whose header sits just before
+// lines[start]: every following line that is blank or indented at least
+// width columns, dedented by width, with trailing blank lines dropped. It
+// also returns the index of the first line after the body.
+func indentedBlock(lines []string, start, width int) ([]string, int) {
+	end := start
+	for j := start; j < len(lines); j++ {
+		if strings.TrimSpace(lines[j]) == "" {
+			continue
+		}
+		if indentWidth(leadingSpace(lines[j])) < width {
+			break
+		}
+		end = j + 1
+	}
+
+	var body []string
+	for _, line := range lines[start:end] {
+		body = append(body, dedent(line, width))
+	}
+	return body, end
+}
+
+// walkAdmonitions calls fn for every admonition in lines, nested ones
+// included, in document order. offset is added to each one's Line, so
+// nested admonitions report their line in the outermost content.
+func walkAdmonitions(lines []string, offset int, fn func(a *admonition, line int)) {
+	for _, node := range parseAdmonitions(lines) {
+		if a := node.admonition; a != nil {
+			fn(a, offset+a.Line)
+			walkAdmonitions(a.Body, offset+a.Line+1, fn)
+		}
+	}
+}
+
+// flattenBlocks moves the body of every admonition and content tab in
+// lines, nested ones included, out to its header's indentation and blanks
+// the header, so the markdown in it parses as the site renders it rather
+// than as indented code. Lines keep their number and order. It also
+// returns which lines were part of a block, headers included.
+func flattenBlocks(lines []string) ([]string, []bool) {
+	flat := append([]string(nil), lines...)
+	inBlock := make([]bool, len(lines))
+	var fence string
+
+	for i, line := range flat {
+		if fence != "" {
+			if isFenceClose(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(line); marker != "" {
+			fence = marker
+			continue
+		}
+
+		match := admonitionHeader.FindStringSubmatch(line)
+		if match == nil {
+			match = tabHeader.FindStringSubmatch(line)
+		}
+		if match == nil {
+			continue
+		}
+
+		// Nested blocks are met again as the loop reaches the moved body.
+		width := indentWidth(match[1]) + admonitionIndent
+		_, end := indentedBlock(flat, i+1, width)
+		flat[i] = ""
+		for j := i; j < end; j++ {
+			inBlock[j] = true
+			if j > i && strings.TrimSpace(flat[j]) != "" {
+				flat[j] = match[1] + dedent(flat[j], width)
+			}
+		}
+	}
 
-	return &AdmonitionConverter{
-		pattern: pattern,
+	return flat, inBlock
+}
+
+// fenceMarker returns the opening run of a fenced code block line (three
+// or more backticks or tildes), or "".
+func fenceMarker(line string) string {
+	trimmed := strings.TrimLeft(line, " \t")
+	for _, c := range []string{"`", "~"} {
+		if strings.HasPrefix(trimmed, c+c+c) {
+			return strings.Repeat(c, len(trimmed)-len(strings.TrimLeft(trimmed, c)))
+		}
+	}
+	return ""
+}
+
+// isFenceClose reports whether line closes a fence opened with marker.
+func isFenceClose(line, marker string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == ""
+}
+
+// leadingSpace returns line's leading spaces and tabs.
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// indentWidth measures indentation in columns, with tabs stopping every
+// four, as Python-Markdown counts them.
+func indentWidth(indent string) int {
+	width := 0
+	for _, c := range indent {
+		if c == '\t' {
+			width += admonitionIndent - width%admonitionIndent
+		} else {
+			width++
+		}
 	}
+	return width
 }
 
-// Convert transforms MkDocs admonition syntax to standard markdown blockquotes.
+// dedent removes up to width columns of indentation from line.
+func dedent(line string, width int) string {
+	removed := 0
+	for i, c := range line {
+		if removed >= width || (c != ' ' && c != '\t') {
+			return line[i:]
+		}
+		if c == '\t' {
+			removed += admonitionIndent - removed%admonitionIndent
+		} else {
+			removed++
+		}
+	}
+	return ""
+}
+
+// githubAlerts maps Material admonition types, aliases included, onto the
+// five GitHub alert types. Any other type is a NOTE.
+var githubAlerts = map[string]string{
+	"tip":       "TIP",
+	"hint":      "TIP",
+	"success":   "TIP",
+	"check":     "TIP",
+	"done":      "TIP",
+	"important": "IMPORTANT",
+	"warning":   "WARNING",
+	"caution":   "WARNING",
+	"attention": "WARNING",
+	"danger":    "CAUTION",
+	"error":     "CAUTION",
+	"failure":   "CAUTION",
+	"fail":      "CAUTION",
+	"missing":   "CAUTION",
+	"bug":       "CAUTION",
+}
+
+// githubAlert returns the GitHub alert type for an admonition type.
+func githubAlert(kind string) string {
+	if alert, ok := githubAlerts[strings.ToLower(kind)]; ok {
+		return alert
+	}
+	return "NOTE"
+}
+
+// AdmonitionConverter implements ports.AdmonitionConverter.
+type AdmonitionConverter struct{}
+
+// NewAdmonitionConverter creates a new admonition converter.
+func NewAdmonitionConverter() *AdmonitionConverter {
+	return &AdmonitionConverter{}
+}
+
+// Convert transforms MkDocs admonition syntax to standard markdown
+// blockquotes, nested ones included, at the indentation they were written
+// at, so one inside a list item stays inside it.
 // Example: !!! tip "Helpful hint" -> > **Helpful hint**
-func (c *AdmonitionConverter) Convert(content string) string {
-	lines := strings.Split(content, "\n")
+//
+// In the GitHub style an admonition becomes an alert instead, with its
+// title kept unless Material would show the default one:
+// !!! danger "Data loss" -> > [!CAUTION] / > **Data loss**. GitHub only
+// renders alerts at the top level of a document, so an admonition nested
+// in another or in a list item stays a plain blockquote.
+func (c *AdmonitionConverter) Convert(content string, style domain.AdmonitionStyle) string {
+	return strings.Join(convertAdmonitions(strings.Split(content, "\n"), style == domain.AdmonitionGitHub), "\n")
+}
+
+// convertAdmonitions converts every admonition in lines to a blockquote,
+// or, if alerts is set, each top-level one to a GitHub alert.
+func convertAdmonitions(lines []string, alerts bool) []string {
+	nodes := parseAdmonitions(lines)
 	var result []string
-	inAdmonition := false
-	var admonitionLines []string
-
-	for i, line := range lines {
-		// Check if this line starts an admonition
-		if match := c.pattern.FindStringSubmatch(line); match != nil {
-			// match[1] = type (abstract, tip, warning, etc.)
-			// match[2] = title
-			inAdmonition = true
-			admonitionLines = []string{
-				"> **" + match[2] + "**",
-				">",
+
+	for i, node := range nodes {
+		a := node.admonition
+		if a == nil {
+			// Don't keep a bare heading marker (##, ###, etc.) left
+			// straight after an admonition.
+			trimmed := strings.TrimSpace(node.line)
+			if i > 0 && nodes[i-1].admonition != nil && trimmed != "" && strings.TrimLeft(trimmed, "#") == "" {
+				continue
 			}
+			result = append(result, node.line)
 			continue
 		}
 
-		// If in admonition, check if line is indented content
-		if inAdmonition {
-			// Admonition content is indented with 4 spaces
-			if strings.HasPrefix(line, "    ") {
-				// Strip 4 spaces and add to blockquote
-				content := strings.TrimPrefix(line, "    ")
-				if strings.TrimSpace(content) == "" {
-					admonitionLines = append(admonitionLines, ">")
-				} else {
-					admonitionLines = append(admonitionLines, "> "+content)
-				}
-			} else if strings.TrimSpace(line) == "" {
-				// Blank line within admonition - add empty blockquote line
-				admonitionLines = append(admonitionLines, ">")
+		var quoted []string
+		switch {
+		case alerts && a.Indent == "":
+			quoted = append(quoted, "[!"+githubAlert(a.Type)+"]")
+			if !a.Untitled && a.Title != "" && !strings.EqualFold(a.Title, a.Type) {
+				quoted = append(quoted, "**"+a.Title+"**", "")
+			}
+		case a.displayTitle() != "":
+			quoted = append(quoted, "**"+a.displayTitle()+"**", "")
+		}
+		quoted = append(quoted, convertAdmonitions(a.Body, false)...)
+		for _, line := range quoted {
+			if strings.TrimSpace(line) == "" {
+				result = append(result, a.Indent+">")
 			} else {
-				// Non-blank, non-indented line - exit admonition
-				result = append(result, admonitionLines...)
-				result = append(result, "")
-
-				// Don't add the line if it's just a heading marker (##, ###, etc.)
-				trimmed := strings.TrimSpace(line)
-				if !(strings.HasPrefix(trimmed, "#") && strings.TrimLeft(trimmed, "#") == "") {
-					result = append(result, line)
-				}
-
-				inAdmonition = false
-				admonitionLines = nil
+				result = append(result, a.Indent+"> "+line)
 			}
-		} else {
-			result = append(result, line)
 		}
 
-		// Handle end of content while still in admonition
-		if i == len(lines)-1 && inAdmonition {
-			result = append(result, admonitionLines...)
+		// A line right after the block would otherwise continue the
+		// blockquote.
+		if i+1 < len(nodes) && (nodes[i+1].admonition != nil || strings.TrimSpace(nodes[i+1].line) != "") {
+			result = append(result, "")
 		}
 	}
 
-	return strings.Join(result, "\n")
+	return result
 }
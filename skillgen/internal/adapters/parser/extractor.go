@@ -2,39 +2,31 @@ package parser
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
-	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
-	"github.com/yuin/goldmark/text"
+	extast "github.com/yuin/goldmark/extension/ast"
 
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
 )
 
-// ContentExtractor implements ports.ContentExtractor using goldmark.
-type ContentExtractor struct {
-	markdown goldmark.Markdown
-}
+// ContentExtractor implements ports.ContentExtractor by walking a goldmark
+// tree.
+type ContentExtractor struct{}
 
 // NewContentExtractor creates a new goldmark-based content extractor.
 func NewContentExtractor() *ContentExtractor {
-	md := goldmark.New()
-	return &ContentExtractor{
-		markdown: md,
-	}
+	return &ContentExtractor{}
 }
 
-// ExtractCodeBlocks finds all fenced code blocks in the markdown.
-func (e *ContentExtractor) ExtractCodeBlocks(content string) []domain.CodeBlock {
-	source := []byte(content)
-	reader := text.NewReader(source)
-
-	// Parse markdown into AST
-	doc := e.markdown.Parser().Parse(reader)
+// ExtractCodeBlocks finds all fenced code blocks in the markdown, those in
+// admonitions and content tabs included.
+func (e *ContentExtractor) ExtractCodeBlocks(parsedDoc ports.ParsedMarkdown) []domain.CodeBlock {
+	md := parsed(parsedDoc)
+	source, doc := md.flat, md.root
 
 	var codeBlocks []domain.CodeBlock
-	lineNum := 0
 
 	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if !entering {
@@ -61,10 +53,8 @@ func (e *ContentExtractor) ExtractCodeBlocks(content string) []domain.CodeBlock
 				Language: language,
 				Content:  strings.TrimSpace(content.String()),
 				Filename: filename,
-				LineNum:  lineNum,
+				Position: md.nodePosition(codeBlock),
 			})
-
-			lineNum++
 		}
 
 		return ast.WalkContinue, nil
@@ -73,16 +63,13 @@ func (e *ContentExtractor) ExtractCodeBlocks(content string) []domain.CodeBlock
 	return codeBlocks
 }
 
-// ExtractMermaid finds all Mermaid diagram blocks.
-func (e *ContentExtractor) ExtractMermaid(content string) []domain.MermaidDiagram {
-	source := []byte(content)
-	reader := text.NewReader(source)
-
-	// Parse markdown into AST
-	doc := e.markdown.Parser().Parse(reader)
+// ExtractMermaid finds all Mermaid diagram blocks, those in admonitions and
+// content tabs included.
+func (e *ContentExtractor) ExtractMermaid(parsedDoc ports.ParsedMarkdown) []domain.MermaidDiagram {
+	md := parsed(parsedDoc)
+	source, doc := md.flat, md.root
 
 	var diagrams []domain.MermaidDiagram
-	lineNum := 0
 
 	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
 		if !entering {
@@ -108,12 +95,10 @@ func (e *ContentExtractor) ExtractMermaid(content string) []domain.MermaidDiagra
 			}
 
 			diagrams = append(diagrams, domain.MermaidDiagram{
-				Content: strings.TrimSpace(diagramContent.String()),
-				Title:   fmt.Sprintf("Diagram %d", lineNum+1),
-				LineNum: lineNum,
+				Content:  strings.TrimSpace(diagramContent.String()),
+				Title:    fmt.Sprintf("Diagram %d", len(diagrams)+1),
+				Position: md.nodePosition(codeBlock),
 			})
-
-			lineNum++
 		}
 
 		return ast.WalkContinue, nil
@@ -122,105 +107,78 @@ func (e *ContentExtractor) ExtractMermaid(content string) []domain.MermaidDiagra
 	return diagrams
 }
 
-// ExtractTables finds all markdown tables.
-func (e *ContentExtractor) ExtractTables(content string) []domain.Table {
-	// Simple regex-based table extraction
-	// Tables in markdown format: | header | header |
-	tablePattern := regexp.MustCompile(`(?m)^\|.+\|\s*$`)
-	lines := strings.Split(content, "\n")
+// ExtractTables finds all GitHub Flavored Markdown tables, those in
+// admonitions and content tabs included. Text that only looks like a table,
+// such as in a code block, is not one.
+func (e *ContentExtractor) ExtractTables(parsedDoc ports.ParsedMarkdown) []domain.Table {
+	md := parsed(parsedDoc)
+	source := md.flat
 
 	var tables []domain.Table
-	var currentTable *domain.Table
-	lineNum := 0
-
-	for i, line := range lines {
-		if tablePattern.MatchString(line) {
-			if currentTable == nil {
-				// Start new table
-				currentTable = &domain.Table{
-					LineNum: lineNum,
-				}
-			}
-
-			// Parse table row
-			cells := parseTableRow(line)
+	ast.Walk(md.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		table, ok := n.(*extast.Table)
+		if !ok || !entering {
+			return ast.WalkContinue, nil
+		}
 
-			// First row is headers (unless it's a separator row)
-			if !isTableSeparator(line) {
-				if len(currentTable.Headers) == 0 {
-					currentTable.Headers = cells
-				} else {
-					currentTable.Rows = append(currentTable.Rows, cells)
-				}
+		t := domain.Table{Position: md.nodePosition(table)}
+		for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+			var cells []string
+			for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+				cells = append(cells, strings.TrimSpace(string(cell.Lines().Value(source))))
 			}
-		} else {
-			// End of table
-			if currentTable != nil {
-				tables = append(tables, *currentTable)
-				currentTable = nil
+			if _, header := row.(*extast.TableHeader); header {
+				t.Headers = cells
+			} else {
+				t.Rows = append(t.Rows, cells)
 			}
 		}
-
-		// Handle end of content while in table
-		if i == len(lines)-1 && currentTable != nil {
-			tables = append(tables, *currentTable)
-		}
-
-		lineNum++
-	}
+		tables = append(tables, t)
+		return ast.WalkSkipChildren, nil
+	})
 
 	return tables
 }
 
-// ExtractAdmonitions finds all Material for MkDocs admonition blocks.
-func (e *ContentExtractor) ExtractAdmonitions(content string) []domain.Admonition {
-	// Pattern matches: !!! type "title"
-	pattern := regexp.MustCompile(`(?m)^!!!\s+(\w+)\s+"([^"]+)"\s*$`)
-	lines := strings.Split(content, "\n")
-
+// ExtractAdmonitions finds all Material for MkDocs admonition blocks:
+// titled or not, collapsible or not, and nested ones, each listed after
+// the block it is nested in. Admonitions are not markdown, so goldmark's
+// tree has no nodes for them; they are read from the parsed source by the
+// grammar the converters share.
+func (e *ContentExtractor) ExtractAdmonitions(parsedDoc ports.ParsedMarkdown) []domain.Admonition {
+	md := parsed(parsedDoc)
 	var admonitions []domain.Admonition
-	var currentAdmonition *domain.Admonition
-	lineNum := 0
-
-	for i, line := range lines {
-		// Check if this line starts an admonition
-		if match := pattern.FindStringSubmatch(line); match != nil {
-			// Save previous admonition if exists
-			if currentAdmonition != nil {
-				admonitions = append(admonitions, *currentAdmonition)
-			}
+	walkAdmonitions(strings.Split(string(md.source), "\n"), 0, func(a *admonition, line int) {
+		admonitions = append(admonitions, domain.Admonition{
+			Type:        a.Type,
+			Title:       a.displayTitle(),
+			Content:     strings.Join(a.Body, "\n"),
+			Collapsible: a.Collapsible,
+			Position:    md.linePosition(line),
+		})
+	})
+	return admonitions
+}
 
-			// Start new admonition
-			currentAdmonition = &domain.Admonition{
-				Type:    match[1],
-				Title:   match[2],
-				LineNum: lineNum,
-			}
-		} else if currentAdmonition != nil {
-			// Collect admonition content (indented with 4 spaces)
-			if strings.HasPrefix(line, "    ") {
-				content := strings.TrimPrefix(line, "    ")
-				if currentAdmonition.Content == "" {
-					currentAdmonition.Content = content
-				} else {
-					currentAdmonition.Content += "\n" + content
-				}
-			} else {
-				// End of admonition (no longer indented)
-				admonitions = append(admonitions, *currentAdmonition)
-				currentAdmonition = nil
-			}
+// ExtractLinks finds every link and image, reference links and those in
+// admonitions and content tabs included, in document order. Destinations are as goldmark decodes them, so backslash
+// escapes and entities are resolved.
+func (e *ContentExtractor) ExtractLinks(parsedDoc ports.ParsedMarkdown) []domain.Link {
+	md := parsed(parsedDoc)
+	var links []domain.Link
+	ast.Walk(md.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
 		}
-
-		// Handle end of content while in admonition
-		if i == len(lines)-1 && currentAdmonition != nil {
-			admonitions = append(admonitions, *currentAdmonition)
+		switch node := n.(type) {
+		case *ast.Link:
+			links = append(links, domain.Link{Dest: string(node.Destination), Position: md.nodePosition(n)})
+		case *ast.Image:
+			links = append(links, domain.Link{Dest: string(node.Destination), Position: md.nodePosition(n)})
 		}
-
-		lineNum++
-	}
-
-	return admonitions
+		return ast.WalkContinue, nil
+	})
+	return links
 }
 
 // Helper functions
@@ -257,23 +215,3 @@ func languageToExtension(language string) string {
 		return language
 	}
 }
-
-func parseTableRow(line string) []string {
-	// Remove leading/trailing pipes
-	line = strings.Trim(line, "|")
-
-	// Split by pipe
-	cells := strings.Split(line, "|")
-
-	// Trim whitespace from each cell
-	for i := range cells {
-		cells[i] = strings.TrimSpace(cells[i])
-	}
-
-	return cells
-}
-
-func isTableSeparator(line string) bool {
-	// Table separator row looks like: |---|---|
-	return strings.Contains(line, "---")
-}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+// nestedContent has a link, code block, diagram, and table at the top
+// level, in an admonition, in a tab, and in an admonition nested in a tab.
+var nestedContent = strings.Join([]string{
+	"# Title",
+	"",
+	"See [top](top.md).",
+	"",
+	"!!! note \"Setup\"",
+	"    Read [inside](admonition.md) first.",
+	"",
+	"    ```yaml",
+	"    key: value",
+	"    ```",
+	"",
+	"    | Name | Value |",
+	"    | ---- | ----- |",
+	"    | a    | 1     |",
+	"",
+	"=== \"Argo\"",
+	"",
+	"    ![diagram](tab.png)",
+	"",
+	"    ```mermaid",
+	"    graph TD",
+	"    ```",
+	"",
+	"    ??? tip",
+	"        ```go",
+	"        package main",
+	"        ```",
+	"",
+	"        See [deep](deep.md).",
+	"",
+	"```bash",
+	"echo top",
+	"```",
+}, "\n")
+
+func TestExtractLinks_InsideAdmonitionsAndTabs(t *testing.T) {
+	doc := NewMarkdownParser().Parse(nestedContent)
+
+	got := NewContentExtractor().ExtractLinks(doc)
+	want := []domain.Link{
+		{Dest: "top.md", Position: domain.Position{Line: 3, Column: 5}},
+		{Dest: "admonition.md", Position: domain.Position{Line: 6, Column: 10}},
+		{Dest: "tab.png", Position: domain.Position{Line: 18, Column: 5}},
+		{Dest: "deep.md", Position: domain.Position{Line: 29, Column: 13}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractLinks = %+v, want %+v", got, want)
+	}
+}
+
+func TestExtractCodeBlocks_InsideAdmonitionsAndTabs(t *testing.T) {
+	doc := NewMarkdownParser().Parse(nestedContent)
+
+	blocks := NewContentExtractor().ExtractCodeBlocks(doc)
+	var got []string
+	for _, b := range blocks {
+		got = append(got, b.Filename+" "+strings.TrimSpace(b.Content))
+	}
+	want := []string{
+		"example-1.yaml key: value",
+		"example-2.mermaid graph TD",
+		"example-3.go package main",
+		"example-4.sh echo top",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractCodeBlocks = %q, want %q", got, want)
+	}
+	if len(blocks) == 4 && blocks[2].Position != (domain.Position{Line: 25, Column: 9}) {
+		t.Errorf("nested code block at %+v, want 25:9", blocks[2].Position)
+	}
+}
+
+func TestExtractMermaid_InsideTabs(t *testing.T) {
+	doc := NewMarkdownParser().Parse(nestedContent)
+
+	diagrams := NewContentExtractor().ExtractMermaid(doc)
+	if len(diagrams) != 1 {
+		t.Fatalf("ExtractMermaid found %d diagrams, want 1", len(diagrams))
+	}
+	if diagrams[0].Position != (domain.Position{Line: 20, Column: 5}) {
+		t.Errorf("diagram at %+v, want 20:5", diagrams[0].Position)
+	}
+}
+
+func TestExtractTables_InsideAdmonitions(t *testing.T) {
+	doc := NewMarkdownParser().Parse(nestedContent)
+
+	tables := NewContentExtractor().ExtractTables(doc)
+	if len(tables) != 1 {
+		t.Fatalf("ExtractTables found %d tables, want 1", len(tables))
+	}
+	if !reflect.DeepEqual(tables[0].Headers, []string{"Name", "Value"}) {
+		t.Errorf("headers = %q, want Name, Value", tables[0].Headers)
+	}
+	if tables[0].Position != (domain.Position{Line: 12, Column: 5}) {
+		t.Errorf("table at %+v, want 12:5", tables[0].Position)
+	}
+}
+
+func TestExtractAdmonitions_KeepsSourcePositions(t *testing.T) {
+	doc := NewMarkdownParser().Parse(nestedContent)
+
+	var got []domain.Position
+	for _, a := range NewContentExtractor().ExtractAdmonitions(doc) {
+		got = append(got, a.Position)
+	}
+	want := []domain.Position{{Line: 5, Column: 1}, {Line: 24, Column: 5}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("admonitions at %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,184 @@
+package parser
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// linkDefinition matches a link reference definition: "[ref]: ../page.md".
+// Group 1 is everything before the destination, and 2 the destination,
+// with any angle brackets.
+var linkDefinition = regexp.MustCompile(`^([ \t]{0,3}\[[^\]]+\]:[ \t]*)(<[^>]*>|\S+)`)
+
+// LinkRewriter implements ports.LinkRewriter using goldmark.
+type LinkRewriter struct {
+	markdown goldmark.Markdown
+}
+
+// NewLinkRewriter creates a new goldmark-based link rewriter.
+func NewLinkRewriter() *LinkRewriter {
+	return &LinkRewriter{markdown: goldmark.New()}
+}
+
+// destinationEdit replaces source[start:end], a link destination, with
+// dest.
+type destinationEdit struct {
+	start, end int
+	dest       string
+}
+
+// Rewrite calls fn with the destination of every inline link and image in
+// content, as written, and of every link reference definition, and puts
+// what fn returns in its place. goldmark finds the links, so text that
+// only looks like one, in code for instance, is left alone; reference
+// links are rewritten through their definitions.
+func (r *LinkRewriter) Rewrite(content string, fn func(dest string) string) string {
+	source := []byte(content)
+	doc := r.markdown.Parser().Parse(text.NewReader(source))
+
+	var edits []destinationEdit
+	cursor := 0
+	ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Text:
+			cursor = max(cursor, node.Segment.Start)
+			return ast.WalkContinue, nil
+		case *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		case *ast.Link, *ast.Image:
+			start, end, ok := inlineDestination(source, n, cursor)
+			if !ok {
+				return ast.WalkContinue, nil
+			}
+			raw := string(source[start:end])
+			if dest := fn(raw); dest != raw {
+				edits = append(edits, destinationEdit{start: start, end: end, dest: dest})
+			}
+			cursor = end
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	edits = append(edits, definitionEdits(content, fn)...)
+
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		source = append(source[:e.start:e.start], append([]byte(e.dest), source[e.end:]...)...)
+	}
+	return string(source)
+}
+
+// inlineDestination finds the destination of the inline link or image n
+// in source: after its text, if it has any, or else after from. It reports
+// false for a reference link, whose destination is in its definition.
+func inlineDestination(source []byte, n ast.Node, from int) (int, int, bool) {
+	open := []byte("[](")
+	if stop := lastTextStop(n); stop >= 0 {
+		// Only closing brackets and emphasis may sit between the text and
+		// "](" of an inline link.
+		i := stop
+		for i < len(source) && bytes.IndexByte([]byte("]*_~`"), source[i]) >= 0 {
+			if source[i] == ']' && i+1 < len(source) && source[i+1] == '(' {
+				return destinationAt(source, i+2)
+			}
+			i++
+		}
+		return 0, 0, false
+	}
+
+	i := bytes.Index(source[from:], open)
+	if i < 0 {
+		return 0, 0, false
+	}
+	return destinationAt(source, from+i+len(open))
+}
+
+// lastTextStop returns where the last text inside n ends, or -1 if n has
+// no text.
+func lastTextStop(n ast.Node) int {
+	stop := -1
+	ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := c.(*ast.Text); ok && entering {
+			stop = max(stop, t.Segment.Stop)
+		}
+		return ast.WalkContinue, nil
+	})
+	return stop
+}
+
+// destinationAt returns the span of the destination starting at or after
+// source[i], just past an inline link's "(": up to the matching ">" of an
+// angle-bracketed one, or else to the first space or unbalanced ")".
+func destinationAt(source []byte, i int) (int, int, bool) {
+	for i < len(source) && (source[i] == ' ' || source[i] == '\t') {
+		i++
+	}
+	if i < len(source) && source[i] == '<' {
+		end := bytes.IndexByte(source[i:], '>')
+		if end < 0 {
+			return 0, 0, false
+		}
+		return i + 1, i + end, true
+	}
+
+	depth := 0
+	for j := i; j < len(source); j++ {
+		switch c := source[j]; {
+		case c == '\\':
+			j++
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == ')' || c == ' ' || c == '\t' || c == '\n':
+			return i, j, true
+		}
+	}
+	return 0, 0, false
+}
+
+// definitionEdits rewrites the destination of each link reference
+// definition outside fenced code in content.
+func definitionEdits(content string, fn func(dest string) string) []destinationEdit {
+	var edits []destinationEdit
+	var fence string
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		start := offset
+		offset += len(line)
+
+		if fence != "" {
+			if isFenceClose(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(line); marker != "" {
+			fence = marker
+			continue
+		}
+
+		match := linkDefinition.FindStringSubmatchIndex(line)
+		if match == nil {
+			continue
+		}
+		destStart, destEnd := match[4], match[5]
+		if line[destStart] == '<' {
+			destStart, destEnd = destStart+1, destEnd-1
+		}
+		raw := line[destStart:destEnd]
+		if dest := fn(raw); dest != raw {
+			edits = append(edits, destinationEdit{start: start + destStart, end: start + destEnd, dest: dest})
+		}
+	}
+	return edits
+}
@@ -2,49 +2,38 @@ package parser
 
 import (
 	"bytes"
+	"regexp"
 	"strings"
 
-	"github.com/yuin/goldmark"
 	"github.com/yuin/goldmark/ast"
-	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
 )
 
-// SectionParser implements ports.SectionParser using goldmark.
-type SectionParser struct {
-	markdown goldmark.Markdown
-}
+// SectionParser implements ports.SectionParser by walking a goldmark tree.
+type SectionParser struct{}
 
 // NewSectionParser creates a new goldmark-based section parser.
 func NewSectionParser() *SectionParser {
-	md := goldmark.New()
-	return &SectionParser{
-		markdown: md,
-	}
+	return &SectionParser{}
 }
 
-// Parse parses markdown and returns a tree of sections.
-func (p *SectionParser) Parse(markdown string) ([]domain.Section, error) {
-	source := []byte(markdown)
-	reader := text.NewReader(source)
-
-	// Parse markdown into AST
-	doc := p.markdown.Parser().Parse(reader)
+// Parse returns the document's tree of sections.
+func (p *SectionParser) Parse(parsedDoc ports.ParsedMarkdown) ([]domain.Section, error) {
+	md := parsed(parsedDoc)
 
 	// Extract sections from AST
-	sections := p.extractSections(doc, source)
+	sections := p.extractSections(md)
 
 	return sections, nil
 }
 
 // ExtractIntroduction extracts content before the first heading (after title).
-func (p *SectionParser) ExtractIntroduction(markdown string) string {
-	source := []byte(markdown)
-	reader := text.NewReader(source)
-
-	// Parse markdown into AST
-	doc := p.markdown.Parser().Parse(reader)
+func (p *SectionParser) ExtractIntroduction(parsedDoc ports.ParsedMarkdown) string {
+	md := parsed(parsedDoc)
+	source, doc := md.source, md.root
 
 	// Find first heading
 	var firstHeadingPos int = -1
@@ -55,12 +44,12 @@ func (p *SectionParser) ExtractIntroduction(markdown string) string {
 			return ast.WalkContinue, nil
 		}
 
-		if heading, ok := n.(*ast.Heading); ok {
-			headingStart := heading.Lines().At(0).Start
+		if heading, ok := n.(*ast.Heading); ok && !md.nested(heading.Pos()) {
+			headingStart := md.offset(heading.Pos())
 
 			// First heading is the title (H1)
 			if heading.Level == 1 && titleHeadingEnd == 0 {
-				titleHeadingEnd = heading.Lines().At(heading.Lines().Len() - 1).Stop
+				titleHeadingEnd = headingEnd(md, heading)
 				return ast.WalkContinue, nil
 			}
 
@@ -92,126 +81,135 @@ func (p *SectionParser) ExtractIntroduction(markdown string) string {
 	return ""
 }
 
-// extractSections walks the AST and extracts sections.
-func (p *SectionParser) extractSections(node ast.Node, source []byte) []domain.Section {
-	// First pass: collect all headings with their positions
-	type headingInfo struct {
-		title    string
-		level    int
-		startPos int
-		endPos   int
-		astNode  ast.Node
-	}
-
-	var headings []headingInfo
+// heading is a heading in a document, with where it starts and where its
+// last line ends.
+type heading struct {
+	node  *ast.Heading
+	title string
+	id    string
+	start int
+	end   int
+}
 
-	ast.Walk(node, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
-		if !entering {
+// extractSections walks the AST and builds the tree of sections. Each
+// heading's section holds every deeper heading after it, up to the next
+// heading at its level or above, however many levels down, and skipped
+// levels are nested all the same. A heading in the body of an admonition
+// or content tab opens no section, so no block is cut in two.
+func (p *SectionParser) extractSections(md *ParsedMarkdown) []domain.Section {
+	var headings []heading
+	ids := make(domain.HeadingIDs)
+	ast.Walk(md.root, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		node, ok := n.(*ast.Heading)
+		if !ok || !entering || md.nested(node.Pos()) {
 			return ast.WalkContinue, nil
 		}
-
-		if heading, ok := n.(*ast.Heading); ok {
-			title := p.extractText(heading, source)
-			startPos := heading.Lines().At(0).Start
-			endPos := heading.Lines().At(heading.Lines().Len() - 1).Stop
-
-			headings = append(headings, headingInfo{
-				title:    title,
-				level:    heading.Level,
-				startPos: startPos,
-				endPos:   endPos,
-				astNode:  n,
-			})
+		title, id := headingAttrs(p.extractText(node, md.flat))
+		if id != "" {
+			ids.Reserve(id)
 		}
-
-		return ast.WalkContinue, nil
+		headings = append(headings, heading{node: node, title: title, id: id, start: md.offset(node.Pos()), end: headingEnd(md, node)})
+		return ast.WalkSkipChildren, nil
 	})
 
-	if len(headings) == 0 {
-		return nil
+	// MkDocs reserves the ids headings set themselves before making up the
+	// rest, so a made-up id never takes one.
+	for i := range headings {
+		if headings[i].id == "" {
+			headings[i].id = ids.Unique(domain.HeadingID(headings[i].title))
+		}
 	}
 
-	// Second pass: build sections with content
-	var sections []domain.Section
+	return p.nestSections(md, headings, len(md.source))
+}
 
-	for i, heading := range headings {
-		// Determine content end position (start of next heading of same or higher level)
-		contentEnd := len(source)
-		for j := i + 1; j < len(headings); j++ {
-			if headings[j].level <= heading.level {
-				contentEnd = headings[j].startPos
-				break
-			}
+// nestSections builds a section for each heading in headings that no
+// earlier one in it holds, with the headings it does hold as subsections.
+// end is where the last of them ends.
+func (p *SectionParser) nestSections(md *ParsedMarkdown, headings []heading, end int) []domain.Section {
+	var sections []domain.Section
+	for i := 0; i < len(headings); {
+		h := headings[i]
+		next := i + 1
+		for next < len(headings) && headings[next].node.Level > h.node.Level {
+			next++
 		}
-
-		// Extract content between heading and next section
-		contentStart := heading.endPos
-		content := strings.TrimSpace(string(source[contentStart:contentEnd]))
-
-		// Remove any trailing heading markers that might have been included
-		content = p.removeTrailingHeadings(content)
-
-		// Build section
-		section := domain.Section{
-			Title:     heading.title,
-			Level:     heading.level,
-			Content:   content,
-			LineStart: heading.startPos,
-			LineEnd:   contentEnd,
+		contentEnd := end
+		if next < len(headings) {
+			contentEnd = headings[next].start
 		}
 
-		// Handle subsections
-		for j := i + 1; j < len(headings); j++ {
-			if headings[j].level <= heading.level {
-				break
-			}
-			if headings[j].level == heading.level+1 {
-				// This is a direct subsection
-				subContentEnd := len(source)
-				for k := j + 1; k < len(headings); k++ {
-					if headings[k].level <= headings[j].level {
-						subContentEnd = headings[k].startPos
-						break
-					}
-				}
-
-				subContentStart := headings[j].endPos
-				subContent := strings.TrimSpace(string(source[subContentStart:subContentEnd]))
-
-				// Remove any trailing heading markers
-				subContent = p.removeTrailingHeadings(subContent)
-
-				subsection := domain.Section{
-					Title:     headings[j].title,
-					Level:     headings[j].level,
-					Content:   subContent,
-					LineStart: headings[j].startPos,
-					LineEnd:   subContentEnd,
-				}
-
-				section.SubSections = append(section.SubSections, subsection)
-			}
-		}
+		sections = append(sections, domain.Section{
+			Title:       h.title,
+			ID:          h.id,
+			Level:       h.node.Level,
+			Content:     p.removeTrailingHeadings(strings.TrimSpace(string(md.source[h.end:contentEnd]))),
+			SubSections: p.nestSections(md, headings[i+1:next], contentEnd),
+			Position:    md.nodePosition(h.node),
+			EndLine:     sectionEndLine(md, h.end, contentEnd),
+		})
+		i = next
+	}
+	return sections
+}
 
-		// Only add top-level sections (subsections are nested)
-		isTopLevel := true
-		for j := 0; j < i; j++ {
-			if headings[j].level < heading.level {
-				// This heading is under a previous heading
-				isTopLevel = false
-				break
-			}
+// headingEnd returns where in the source the last line of heading ends:
+// past an attr_list or closing hashes, and past the underline of a setext
+// heading.
+func headingEnd(md *ParsedMarkdown, heading *ast.Heading) int {
+	lineEnd := func(i int) int {
+		if j := bytes.IndexByte(md.flat[i:], '\n'); j >= 0 {
+			return i + j
 		}
+		return len(md.flat)
+	}
 
-		if isTopLevel {
-			sections = append(sections, section)
-		}
+	end := heading.Pos()
+	if n := heading.Lines().Len(); n > 0 {
+		end = max(end, heading.Lines().At(n-1).Stop-1)
 	}
+	end = lineEnd(end)
+	if md.flat[heading.Pos()] != '#' && end < len(md.flat) {
+		end = lineEnd(end + 1)
+	}
+	return md.offset(end)
+}
 
-	return sections
+// sectionEndLine returns the line of the last character of a section
+// whose heading ends at headingEnd and whose content ends at contentEnd,
+// blank lines aside: the heading's own line if it has no content.
+func sectionEndLine(md *ParsedMarkdown, headingEnd, contentEnd int) int {
+	end := len(bytes.TrimRight(md.source[:contentEnd], " \t\n"))
+	return md.position(max(end, headingEnd, 1) - 1).Line
 }
 
-// extractText extracts plain text from an AST node.
+// headingAttrList matches an attr_list at the end of a heading, as
+// Python-Markdown's attr_list extension reads one: "Setup { #setup .wide }".
+// Group 1 is what is inside the braces.
+var headingAttrList = regexp.MustCompile(`[ \t]+\{:?([^}\n]*)\}[ \t]*$`)
+
+// attrListID matches an id in an attr_list: "#setup".
+var attrListID = regexp.MustCompile(`(?:^|[ \t])#([^ \t=}]+)`)
+
+// headingAttrs cuts an attr_list off the end of a heading's text, and
+// returns the text and the id the attr_list sets, if any. The last id set
+// wins, as in MkDocs.
+// Example: "Setup { #setup .wide }" -> "Setup", "setup"
+func headingAttrs(text string) (string, string) {
+	match := headingAttrList.FindStringSubmatchIndex(text)
+	if match == nil {
+		return text, ""
+	}
+	id := ""
+	for _, m := range attrListID.FindAllStringSubmatch(text[match[2]:match[3]], -1) {
+		id = m[1]
+	}
+	return strings.TrimSpace(text[:match[0]]), id
+}
+
+// extractText extracts the text of an AST node as MkDocs renders it, markup
+// aside: inline code and the text of links kept, and backslash escapes and
+// entities resolved.
 func (p *SectionParser) extractText(node ast.Node, source []byte) string {
 	var buf bytes.Buffer
 
@@ -222,9 +220,18 @@ func (p *SectionParser) extractText(node ast.Node, source []byte) string {
 
 		switch v := n.(type) {
 		case *ast.Text:
-			buf.Write(v.Segment.Value(source))
+			value := v.Segment.Value(source)
+			if !v.IsRaw() {
+				value = util.UnescapePunctuations(util.ResolveNumericReferences(util.ResolveEntityNames(value)))
+			}
+			buf.Write(value)
+			if v.SoftLineBreak() || v.HardLineBreak() {
+				buf.WriteByte(' ')
+			}
 		case *ast.String:
 			buf.Write(v.Value)
+		case *ast.AutoLink:
+			buf.Write(v.Label(source))
 		}
 
 		return ast.WalkContinue, nil
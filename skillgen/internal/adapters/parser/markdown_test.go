@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExtractSections_LeavesAdmonitionsWhole(t *testing.T) {
+	content := strings.Join([]string{
+		"# Title",
+		"",
+		"Intro.",
+		"",
+		"!!! note",
+		"    ## Not a section",
+		"",
+		"    Still the note.",
+		"",
+		"## Usage",
+		"",
+		"=== \"Tab\"",
+		"    ### Nor this",
+		"",
+		"Done.",
+	}, "\n")
+	doc := NewMarkdownParser().Parse(content)
+	p := NewSectionParser()
+
+	intro := p.ExtractIntroduction(doc)
+	if !strings.HasSuffix(intro, "    Still the note.") {
+		t.Errorf("introduction = %q, want the whole note", intro)
+	}
+
+	sections, err := p.Parse(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sections) != 1 || len(sections[0].SubSections) != 1 {
+		t.Fatalf("sections = %+v, want Title holding only Usage", sections)
+	}
+	usage := sections[0].SubSections[0]
+	if usage.Title != "Usage" || len(usage.SubSections) != 0 {
+		t.Errorf("subsection = %+v, want Usage with none nested", usage)
+	}
+	if !strings.HasPrefix(usage.Content, "=== \"Tab\"\n    ### Nor this") {
+		t.Errorf("Usage content = %q, want the tab as written", sections[1].Content)
+	}
+	if usage.Position.Line != 10 || usage.EndLine != 15 {
+		t.Errorf("Usage spans %d-%d, want 10-15", usage.Position.Line, usage.EndLine)
+	}
+}
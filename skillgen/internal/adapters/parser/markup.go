@@ -0,0 +1,357 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+// attrListValue is one attr_list entry: a class, an id, or a key=value.
+const attrListValue = `(?:[.#][\w-]+|[\w-]+=(?:"[^"]*"|'[^']*'|[^\s}]+))`
+
+// attrList matches an attr_list suffix, such as "{ .md-button }",
+// "{ #anchor }", or "{: target=_blank }", with any whitespace before it.
+var attrList = regexp.MustCompile(`[ \t]*\{:?[ \t]*` + attrListValue + `(?:[ \t]+` + attrListValue + `)*[ \t]*\}`)
+
+// macro matches an mkdocs-macros expression. A GitHub Actions "${{ ... }}"
+// is not one; callers check the character before the match.
+var macro = regexp.MustCompile(`\{\{.*?\}\}`)
+
+// shortcode matches an emoji or icon shortcode. Only icons (see iconSet)
+// and the emoji in emojiShortcodes are treated as shortcodes, so text such
+// as "10:30:45" is left alone.
+var shortcode = regexp.MustCompile(`:([a-z0-9_+-]+):`)
+
+// iconSets are the prefixes of the icon sets Material bundles.
+var iconSets = []string{"material-", "octicons-", "fontawesome-", "simple-"}
+
+// iconWords maps words in an icon's name to a Unicode character with the
+// same meaning. Icons with none are dropped.
+var iconWords = []struct {
+	word, char string
+}{
+	{"check", "✓"},
+	{"close", "✗"},
+	{"x-circle", "✗"},
+	{"xmark", "✗"},
+	{"alert", "⚠"},
+	{"warning", "⚠"},
+	{"information", "ℹ"},
+	{"info", "ℹ"},
+	{"lightbulb", "💡"},
+	{"lock", "🔒"},
+	{"arrow-right", "→"},
+	{"arrow-left", "←"},
+}
+
+// emojiShortcodes maps the emoji shortcodes the docs use to their Unicode
+// characters.
+var emojiShortcodes = map[string]string{
+	"white_check_mark":   "✅",
+	"heavy_check_mark":   "✔️",
+	"x":                  "❌",
+	"warning":            "⚠️",
+	"no_entry":           "⛔",
+	"information_source": "ℹ️",
+	"bulb":               "💡",
+	"rocket":             "🚀",
+	"tada":               "🎉",
+	"fire":               "🔥",
+	"star":               "⭐",
+	"memo":               "📝",
+	"lock":               "🔒",
+	"construction":       "🚧",
+	"+1":                 "👍",
+	"thumbsup":           "👍",
+	"-1":                 "👎",
+	"thumbsdown":         "👎",
+}
+
+// annotationMarker matches a code line ending in an annotation marker
+// inside a comment: "image: nginx # (1)!". Group 1 is the code before the
+// comment, 2 the comment opener, 3 any comment text before the marker, 4
+// the marker itself, 5 its number, and 6 its "!", which asks Material to
+// hide the comment characters.
+var annotationMarker = regexp.MustCompile(`^((?:.*[ \t])?)((?:#|//|--|;|/\*|<!--)[ \t]*)(.*?)[ \t]*(\((\d+)\)(!)?)[ \t]*(?:\*/|-->)?[ \t]*$`)
+
+// orderedItem matches the first line of an ordered list item.
+var orderedItem = regexp.MustCompile(`^([ \t]*)\d+\.[ \t]+`)
+
+// MarkupNormalizer implements ports.MarkupNormalizer.
+type MarkupNormalizer struct{}
+
+// NewMarkupNormalizer creates a new markup normalizer.
+func NewMarkupNormalizer() *MarkupNormalizer {
+	return &MarkupNormalizer{}
+}
+
+// Normalize applies rules to content. attr_list, emoji, and macros are only
+// looked for outside code, where MkDocs would act on them; annotations only
+// in a fenced code block followed by the ordered list that holds them, as
+// Material requires. Flagged markup is placed in content.
+// Example: [Start](x){ .md-button } :material-check: -> [Start](x) ✓
+func (n *MarkupNormalizer) Normalize(content string, rules domain.MarkupRules) (string, []domain.FlaggedMarkup) {
+	source := strings.Split(content, "\n")
+	var flagged []domain.FlaggedMarkup
+	flagAt := func(c domain.MarkupConstruct, text string, line, offset int) {
+		// Markup stripped earlier on the line moves later matches left, so
+		// look for the text from where it was seen.
+		if k := strings.Index(source[line][offset:], text); k >= 0 {
+			offset += k
+		}
+		position := domain.Position{Line: line + 1, Column: utf8.RuneCountInString(source[line][:offset]) + 1}
+		flagged = append(flagged, domain.FlaggedMarkup{Construct: c, Text: text, Position: position})
+	}
+
+	lines, origin := normalizeAnnotations(source, rules.Action(domain.MarkupAnnotations), flagAt)
+
+	var fence string
+	for i, line := range lines {
+		if fence != "" {
+			if isFenceClose(line, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if marker := fenceMarker(line); marker != "" {
+			fence = marker
+			continue
+		}
+
+		normalized := outsideCodeSpans(line, func(text string, start int) string {
+			flag := func(c domain.MarkupConstruct, match string, offset int) {
+				flagAt(c, match, origin[i], start+offset)
+			}
+			text = normalizeMacros(text, rules.Action(domain.MarkupMacros), flag)
+			text = normalizeShortcodes(text, rules.Action(domain.MarkupEmoji), flag)
+			return normalizeAttrLists(text, rules.Action(domain.MarkupAttrList), flag)
+		})
+		if normalized != line {
+			normalized = strings.TrimRight(normalized, " \t")
+		}
+		lines[i] = normalized
+	}
+
+	return strings.Join(lines, "\n"), flagged
+}
+
+// markupFlag reports a construct's text, and its offset in the text it was
+// found in.
+type markupFlag func(c domain.MarkupConstruct, text string, offset int)
+
+// normalizeMacros applies action to each mkdocs-macros expression in text.
+func normalizeMacros(text string, action domain.MarkupAction, flag markupFlag) string {
+	return replaceMatches(macro, text, func(match string, start int) string {
+		if start > 0 && text[start-1] == '$' {
+			return match
+		}
+		if action == domain.MarkupFlag {
+			flag(domain.MarkupMacros, match, start)
+			return match
+		}
+		return ""
+	})
+}
+
+// normalizeShortcodes applies action to each icon and known emoji
+// shortcode in text.
+func normalizeShortcodes(text string, action domain.MarkupAction, flag markupFlag) string {
+	return replaceMatches(shortcode, text, func(match string, start int) string {
+		plain, ok := plainShortcode(strings.Trim(match, ":"))
+		if !ok {
+			return match
+		}
+		switch action {
+		case domain.MarkupFlag:
+			flag(domain.MarkupEmoji, match, start)
+			return match
+		case domain.MarkupPlain:
+			return plain
+		}
+		return ""
+	})
+}
+
+// plainShortcode returns the Unicode character for an icon or emoji
+// shortcode name, "" for an icon with none, and false if name is neither.
+func plainShortcode(name string) (string, bool) {
+	if char, ok := emojiShortcodes[name]; ok {
+		return char, true
+	}
+	for _, set := range iconSets {
+		if !strings.HasPrefix(name, set) {
+			continue
+		}
+		for _, w := range iconWords {
+			if strings.Contains(name, w.word) {
+				return w.char, true
+			}
+		}
+		return "", true
+	}
+	return "", false
+}
+
+// normalizeAttrLists applies action to each attr_list suffix in text.
+func normalizeAttrLists(text string, action domain.MarkupAction, flag markupFlag) string {
+	return replaceMatches(attrList, text, func(match string, start int) string {
+		if action == domain.MarkupFlag {
+			flag(domain.MarkupAttrList, strings.TrimSpace(match), start)
+			return match
+		}
+		return ""
+	})
+}
+
+// replaceMatches replaces each match of re in text with what fn returns
+// for it, given the match and its offset in text.
+func replaceMatches(re *regexp.Regexp, text string, fn func(match string, start int) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		replacement := fn(text[loc[0]:loc[1]], loc[0])
+		b.WriteString(text[last:loc[0]])
+		b.WriteString(replacement)
+		last = loc[1]
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// outsideCodeSpans applies fn to the parts of line outside inline code
+// spans, given each part and its offset in line, leaving the spans as
+// written.
+func outsideCodeSpans(line string, fn func(text string, start int) string) string {
+	var b strings.Builder
+	rest := line
+	for {
+		open := strings.Index(rest, "`")
+		if open < 0 {
+			break
+		}
+		run := len(rest[open:]) - len(strings.TrimLeft(rest[open:], "`"))
+		ticks := rest[open : open+run]
+		end := strings.Index(rest[open+run:], ticks)
+		if end < 0 {
+			break
+		}
+		end += open + 2*run
+		b.WriteString(fn(rest[:open], len(line)-len(rest)))
+		b.WriteString(rest[open:end])
+		rest = rest[end:]
+	}
+	b.WriteString(fn(rest, len(line)-len(rest)))
+	return b.String()
+}
+
+// normalizeAnnotations applies action to the annotation markers in each
+// fenced code block that is followed by an ordered list, and to that list.
+// It also returns the index in lines each line of the result came from.
+// flag is given a marker's comment, and its line and offset in lines.
+func normalizeAnnotations(lines []string, action domain.MarkupAction, flag func(c domain.MarkupConstruct, text string, line, offset int)) ([]string, []int) {
+	var result []string
+	var origin []int
+	keep := func(from int, kept ...string) {
+		result = append(result, kept...)
+		for k := range kept {
+			origin = append(origin, from+k)
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		marker := fenceMarker(lines[i])
+		if marker == "" {
+			keep(i, lines[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && !isFenceClose(lines[end], marker) {
+			end++
+		}
+		if end == len(lines) {
+			keep(i, lines[i:]...)
+			break
+		}
+
+		indent := indentWidth(leadingSpace(lines[i]))
+		listEnd := annotationList(lines, end+1, indent)
+		var markers []int
+		for j := i + 1; j < end && listEnd >= 0; j++ {
+			if annotationMarker.MatchString(lines[j]) {
+				markers = append(markers, j)
+			}
+		}
+		if len(markers) == 0 {
+			keep(i, lines[i:end+1]...)
+			i = end
+			continue
+		}
+
+		block := append([]string(nil), lines[i:end+1]...)
+		for _, j := range markers {
+			match := annotationMarker.FindStringSubmatchIndex(lines[j])
+			line := lines[j]
+			switch action {
+			case domain.MarkupFlag:
+				flag(domain.MarkupAnnotations, strings.TrimSpace(line[match[4]:]), j, match[4])
+			case domain.MarkupPlain:
+				if match[12] >= 0 {
+					block[j-i] = line[:match[12]] + line[match[13]:]
+				}
+			default:
+				if line[match[6]:match[7]] == "" {
+					block[j-i] = strings.TrimRight(line[:match[3]], " \t")
+				} else {
+					block[j-i] = line[:match[7]]
+				}
+			}
+		}
+		keep(i, block...)
+
+		i = end
+		if action == domain.MarkupStrip {
+			// Drop the list, and keep whatever follows it out of the
+			// code block's paragraph.
+			if listEnd < len(lines) && strings.TrimSpace(lines[listEnd]) != "" {
+				keep(listEnd, "")
+			}
+			i = listEnd - 1
+		}
+	}
+
+	return result, origin
+}
+
+// annotationList finds the ordered list that holds a code block's
+// annotations, starting at or after lines[start] past blank lines, at the
+// code block's indent. It returns the index of the line after the list, or
+// -1 if there is no such list.
+func annotationList(lines []string, start, indent int) int {
+	first := start
+	for first < len(lines) && strings.TrimSpace(lines[first]) == "" {
+		first++
+	}
+	if first == len(lines) {
+		return -1
+	}
+	match := orderedItem.FindStringSubmatch(lines[first])
+	if match == nil || indentWidth(match[1]) != indent {
+		return -1
+	}
+
+	end := first + 1
+	for j := first + 1; j < len(lines); j++ {
+		if strings.TrimSpace(lines[j]) == "" {
+			continue
+		}
+		width := indentWidth(leadingSpace(lines[j]))
+		if width < indent || (width == indent && !orderedItem.MatchString(lines[j])) {
+			break
+		}
+		end = j + 1
+	}
+	return end
+}
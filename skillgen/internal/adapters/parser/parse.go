@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// MarkdownParser implements ports.MarkdownParser using goldmark with the
+// GitHub Flavored Markdown extension.
+type MarkdownParser struct {
+	markdown goldmark.Markdown
+}
+
+// NewMarkdownParser creates a new goldmark-based markdown parser.
+func NewMarkdownParser() *MarkdownParser {
+	return &MarkdownParser{markdown: goldmark.New(goldmark.WithExtensions(extension.GFM))}
+}
+
+// Parse parses markdown into the syntax tree every extractor walks. The
+// bodies of admonitions and content tabs are moved out to their headers'
+// indentation first, so the links, code, and tables in them parse as the
+// site renders them rather than as indented code.
+func (p *MarkdownParser) Parse(markdown string) ports.ParsedMarkdown {
+	source := []byte(markdown)
+	lines := strings.Split(markdown, "\n")
+	flatLines, inBlock := flattenBlocks(lines)
+	flat := []byte(strings.Join(flatLines, "\n"))
+
+	lineStarts := make([]int, len(lines))
+	flatStarts := make([]int, len(lines))
+	shift := make([]int, len(lines))
+	for i := 1; i < len(lines); i++ {
+		lineStarts[i] = lineStarts[i-1] + len(lines[i-1]) + 1
+		flatStarts[i] = flatStarts[i-1] + len(flatLines[i-1]) + 1
+	}
+	for i := range lines {
+		shift[i] = len(lines[i]) - len(flatLines[i])
+	}
+	return &ParsedMarkdown{
+		source:     source,
+		flat:       flat,
+		root:       p.markdown.Parser().Parse(text.NewReader(flat)),
+		lineStarts: lineStarts,
+		flatStarts: flatStarts,
+		shift:      shift,
+		inBlock:    inBlock,
+	}
+}
+
+// ParsedMarkdown implements ports.ParsedMarkdown: goldmark's syntax tree
+// along with the flattened source its segments point into, and how each of
+// its lines maps back to the markdown as given.
+type ParsedMarkdown struct {
+	source     []byte
+	flat       []byte
+	root       ast.Node
+	lineStarts []int  // where each line of source starts
+	flatStarts []int  // where each line of flat starts
+	shift      []int  // how many bytes longer each line of source is than in flat
+	inBlock    []bool // whether each line is in an admonition or tab, header included
+}
+
+// Source returns the markdown that was parsed.
+func (d *ParsedMarkdown) Source() string {
+	return string(d.source)
+}
+
+// position returns the 1-based line and column of the byte at offset.
+func (d *ParsedMarkdown) position(offset int) domain.Position {
+	line := sort.Search(len(d.lineStarts), func(i int) bool { return d.lineStarts[i] > offset }) - 1
+	start := d.lineStarts[line]
+	return domain.Position{Line: line + 1, Column: utf8.RuneCount(d.source[start:offset]) + 1}
+}
+
+// nodePosition returns where n starts in the source: past the indentation
+// of a block, and at the "[" or "![" of a link or image. A node goldmark
+// placed nowhere has no position.
+func (d *ParsedMarkdown) nodePosition(n ast.Node) domain.Position {
+	if n.Pos() < 0 {
+		return domain.Position{}
+	}
+	return d.position(d.offset(n.Pos()))
+}
+
+// flatLine returns the index, counted from 0, of the line of the tree's
+// source that offset falls on.
+func (d *ParsedMarkdown) flatLine(offset int) int {
+	return sort.Search(len(d.flatStarts), func(i int) bool { return d.flatStarts[i] > offset }) - 1
+}
+
+// offset returns where the byte at offset in the tree's source is in the
+// markdown as given.
+func (d *ParsedMarkdown) offset(flat int) int {
+	line := d.flatLine(flat)
+	return d.lineStarts[line] + d.shift[line] + flat - d.flatStarts[line]
+}
+
+// nested reports whether the node at offset in the tree's source came from
+// the body of an admonition or content tab.
+func (d *ParsedMarkdown) nested(offset int) bool {
+	return d.inBlock[d.flatLine(offset)]
+}
+
+// linePosition returns the position of the first character past the
+// indentation of the line at index, counted from 0.
+func (d *ParsedMarkdown) linePosition(index int) domain.Position {
+	if index < 0 || index >= len(d.lineStarts) {
+		return domain.Position{}
+	}
+	line := d.source[d.lineStarts[index]:]
+	if end := bytes.IndexByte(line, '\n'); end >= 0 {
+		line = line[:end]
+	}
+	indent := len(line) - len(bytes.TrimLeft(line, " \t"))
+	return d.position(d.lineStarts[index] + indent)
+}
+
+// defaultParser parses markdown handed to an extractor by another
+// ports.MarkdownParser, whose tree it can't read.
+var defaultParser = NewMarkdownParser()
+
+// parsed returns doc's goldmark tree, parsing its source again only if
+// doc came from another parser.
+func parsed(doc ports.ParsedMarkdown) *ParsedMarkdown {
+	if d, ok := doc.(*ParsedMarkdown); ok {
+		return d
+	}
+	return defaultParser.Parse(doc.Source()).(*ParsedMarkdown)
+}
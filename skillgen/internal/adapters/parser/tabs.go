@@ -0,0 +1,88 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tabHeader matches the first line of a pymdownx content tab, at any
+// indentation:
+//
+//	=== "GitHub Actions"
+//	===! "Starts a new tab set"
+//	===+ "Selected by default"
+//
+// Group 1 is the indentation, 2 any ! and + modifiers, and 3 the title.
+var tabHeader = regexp.MustCompile(`^([ \t]*)===([!+]*)[ \t]+"(.*)"[ \t]*$`)
+
+// TabConverter implements ports.TabConverter.
+type TabConverter struct{}
+
+// NewTabConverter creates a new content tab converter.
+func NewTabConverter() *TabConverter {
+	return &TabConverter{}
+}
+
+// Convert replaces each pymdownx content tab with its title in bold
+// followed by its de-indented body, so every variant of a tab set reads
+// one after another instead of rendering as an indented code block. Tabs
+// nested in another tab, an admonition, or a list item keep that
+// indentation; tab headers inside fenced code blocks are left alone.
+// Example: === "Argo" -> **Argo**
+func (c *TabConverter) Convert(content string) string {
+	return strings.Join(convertTabs(strings.Split(content, "\n")), "\n")
+}
+
+// convertTabs converts every content tab in lines.
+func convertTabs(lines []string) []string {
+	var result []string
+	var fence string
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		if fence != "" {
+			if isFenceClose(line, fence) {
+				fence = ""
+			}
+			result = append(result, line)
+			continue
+		}
+		if marker := fenceMarker(line); marker != "" {
+			fence = marker
+			result = append(result, line)
+			continue
+		}
+
+		match := tabHeader.FindStringSubmatch(line)
+		if match == nil {
+			result = append(result, line)
+			continue
+		}
+
+		indent := match[1]
+		body, end := indentedBlock(lines, i+1, indentWidth(indent)+admonitionIndent)
+		for len(body) > 0 && strings.TrimSpace(body[0]) == "" {
+			body = body[1:]
+		}
+		if title := strings.TrimSpace(match[3]); title != "" {
+			result = append(result, indent+"**"+title+"**", "")
+		}
+		for _, bodyLine := range convertTabs(body) {
+			if strings.TrimSpace(bodyLine) == "" {
+				result = append(result, "")
+			} else {
+				result = append(result, indent+bodyLine)
+			}
+		}
+
+		// Keep the next tab's title, or the content after the tab set,
+		// out of this tab's last paragraph.
+		if end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+			result = append(result, "")
+		}
+		i = end - 1
+	}
+
+	return result
+}
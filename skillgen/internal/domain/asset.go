@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DefaultAssetMaxSize is the largest asset bundled when a plugin sets no
+// cap of its own: 1 MiB.
+const DefaultAssetMaxSize int64 = 1 << 20
+
+// DefaultAssetExtensions are the asset types bundled when a plugin lists
+// none: images, and the config and script files docs offer for download.
+var DefaultAssetExtensions = []string{
+	".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg",
+	".yaml", ".yml", ".json", ".toml", ".txt", ".csv", ".sh",
+}
+
+// AssetRules choose which local files a doc links to are copied into the
+// hub's library/ beside it. An empty field uses its default.
+type AssetRules struct {
+	// MaxSize is the largest asset bundled, in bytes. If zero, uses
+	// DefaultAssetMaxSize.
+	MaxSize int64 `json:"maxSize,omitempty"`
+
+	// Extensions are the file extensions bundled, with or without the
+	// leading dot, in any case. If empty, uses DefaultAssetExtensions.
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// Check returns why the asset at name, of size bytes, is not bundled, or ""
+// if it is.
+func (r AssetRules) Check(name string, size int) string {
+	ext := strings.ToLower(path.Ext(name))
+	extensions := r.Extensions
+	if len(extensions) == 0 {
+		extensions = DefaultAssetExtensions
+	}
+	allowed := false
+	for _, e := range extensions {
+		if ext != "" && ext == "."+strings.ToLower(strings.TrimPrefix(e, ".")) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return fmt.Sprintf("extension %q is not among the bundled asset types", ext)
+	}
+
+	maxSize := r.MaxSize
+	if maxSize == 0 {
+		maxSize = DefaultAssetMaxSize
+	}
+	if int64(size) > maxSize {
+		return fmt.Sprintf("%d bytes is over the %d-byte asset size cap", size, maxSize)
+	}
+	return ""
+}
+
+// Validate reports a negative size cap or an empty extension.
+func (r AssetRules) Validate() error {
+	if r.MaxSize < 0 {
+		return fmt.Errorf("assets maxSize %d must not be negative", r.MaxSize)
+	}
+	for _, e := range r.Extensions {
+		if strings.TrimPrefix(e, ".") == "" {
+			return fmt.Errorf("assets extensions must not include an empty extension")
+		}
+	}
+	return nil
+}
+
+// LibraryAsset is a local file a doc links to, such as a diagram or a
+// downloadable config, shipped under the hub's library/ directory so the
+// link still works offline.
+type LibraryAsset struct {
+	RelPath    string // Path relative to the hub's library/ directory
+	Content    []byte
+	SourcePath string // Original file path
+}
+
+// SkippedAsset is a local file a doc links to that was not bundled, so its
+// link points at the docs site instead.
+type SkippedAsset struct {
+	Path     string // Path relative to the docs root
+	Reason   string
+	Position // of the doc's first link to it
+}
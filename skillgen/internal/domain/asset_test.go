@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssetRules_Check(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   AssetRules
+		file    string
+		size    int
+		wantSub string
+	}{
+		{"default type", AssetRules{}, "a/flow.PNG", 100, ""},
+		{"default cap", AssetRules{}, "a/flow.png", int(DefaultAssetMaxSize) + 1, "over the 1048576-byte asset size cap"},
+		{"unlisted type", AssetRules{}, "a/bundle.zip", 100, `extension ".zip" is not among`},
+		{"no extension", AssetRules{}, "a/Makefile", 100, `extension ""`},
+		{"own types, with or without dot", AssetRules{Extensions: []string{"zip", ".TGZ"}}, "a/x.tgz", 100, ""},
+		{"own types replace the defaults", AssetRules{Extensions: []string{"zip"}}, "a/flow.png", 100, `".png"`},
+		{"own cap", AssetRules{MaxSize: 10}, "a/flow.svg", 11, "over the 10-byte"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.rules.Check(tt.file, tt.size)
+			if tt.wantSub == "" && got != "" || !strings.Contains(got, tt.wantSub) {
+				t.Errorf("Check(%q, %d) = %q, want %q", tt.file, tt.size, got, tt.wantSub)
+			}
+		})
+	}
+}
+
+func TestAssetRules_Validate(t *testing.T) {
+	if err := (AssetRules{MaxSize: 10, Extensions: []string{".png"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (AssetRules{MaxSize: -1}).Validate(); err == nil {
+		t.Error("expected an error for a negative cap")
+	}
+	if err := (AssetRules{Extensions: []string{"."}}).Validate(); err == nil {
+		t.Error("expected an error for an empty extension")
+	}
+}
@@ -0,0 +1,33 @@
+package domain
+
+// CategoryCache is what the previous run of one category parsed, built, and
+// wrote, so an unchanged category can skip all three.
+type CategoryCache struct {
+	// Generator fingerprints the skillgen build that wrote the entry. Parsed
+	// documents are only reused by the same build.
+	Generator string `json:"generator"`
+
+	// Key hashes every input the hub is built from: the generator, the
+	// templates, the plugin's metadata, and the content hash of each
+	// document and each snippet file they include.
+	Key string `json:"key"`
+
+	// Documents holds each parsed document by its path relative to the docs
+	// root.
+	Documents map[string]CachedDocument `json:"documents"`
+
+	// Hub is the hub skill built from Documents.
+	Hub *Skill `json:"hub,omitempty"`
+
+	// Outputs maps each file written for the hub to the sha256 of its
+	// content, so a cache hit can confirm the output is still in place.
+	Outputs map[string]string `json:"outputs,omitempty"`
+}
+
+// CachedDocument is one parsed document and the hash of the content it was
+// parsed from, plus the hash of each snippet file it included, by path.
+type CachedDocument struct {
+	Hash     string            `json:"hash"`
+	Snippets map[string]string `json:"snippets,omitempty"`
+	Document *Document         `json:"document"`
+}
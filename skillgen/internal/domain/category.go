@@ -1,17 +1,117 @@
 package domain
 
-// Categories are the plugin collections a skill can belong to. They map
-// one-to-one onto top-level directories in the docs tree and onto plugin
-// directories in the generated marketplace, so a document outside these
-// directories produces no skill.
-var Categories = []string{"patterns", "enforce", "build", "secure"}
-
-// IsCategory reports whether name is a known plugin collection.
-func IsCategory(name string) bool {
-	for _, c := range Categories {
-		if c == name {
-			return true
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Category is a plugin collection: one key in plugin-metadata.json's plugins
+// map, one hub skill, and one directory in the docs tree. A document outside
+// every category's source directory produces no skill.
+type Category struct {
+	// Name is the plugin key. It names the plugin directory and hub skill.
+	Name string
+
+	// SourceDir is the slash-separated docs directory the collection is built
+	// from, relative to the docs root. It is also the collection's URL path
+	// on the docs site.
+	SourceDir string
+}
+
+// NewCategory returns the category for a plugin, defaulting its source
+// directory to the plugin key.
+func NewCategory(pluginKey string, cfg PluginConfig) Category {
+	sourceDir := strings.Trim(filepath.ToSlash(cfg.SourceDir), "/")
+	if sourceDir == "" {
+		sourceDir = pluginKey
+	}
+	return Category{Name: pluginKey, SourceDir: sourceDir}
+}
+
+// CategorySet is the set of categories a run generates, ordered by name.
+type CategorySet []Category
+
+// Categories returns one category per plugin, ordered by plugin key so that
+// generation order never depends on map iteration.
+func (m *PluginMetadata) Categories() CategorySet {
+	set := make(CategorySet, 0, len(m.Plugins))
+	for key, cfg := range m.Plugins {
+		set = append(set, NewCategory(key, cfg))
+	}
+	sort.Slice(set, func(i, j int) bool { return set[i].Name < set[j].Name })
+	return set
+}
+
+// Names returns the category names in order.
+func (s CategorySet) Names() []string {
+	names := make([]string, len(s))
+	for i, c := range s {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Lookup returns the category called name.
+func (s CategorySet) Lookup(name string) (Category, bool) {
+	for _, c := range s {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Category{}, false
+}
+
+// Contains reports whether name is a category in the set.
+func (s CategorySet) Contains(name string) bool {
+	_, ok := s.Lookup(name)
+	return ok
+}
+
+// Locate finds the category whose source directory contains the document at
+// path, relative to the docs root, and returns the directory segments
+// strictly between that source directory and the document's filename,
+// whatever the filename is. For patterns/architecture/hub-and-spoke/index.md
+// and a category with source directory "patterns" it returns
+// ["architecture", "hub-and-spoke"].
+//
+// Source directories are relative to the docs root too, so only a match at
+// the start of path counts, however the directories above the docs root
+// are named. The deepest matching source directory wins.
+func (s CategorySet) Locate(path string) (Category, []string, bool) {
+	parts := strings.Split(filepath.ToSlash(filepath.Clean(path)), "/")
+
+	// Drop the filename; only directories name a category.
+	if len(parts) > 0 && strings.EqualFold(filepath.Ext(parts[len(parts)-1]), ".md") {
+		parts = parts[:len(parts)-1]
+	}
+
+	var (
+		best     Category
+		bestSize int
+	)
+	for _, c := range s {
+		dir := strings.Split(c.SourceDir, "/")
+		if len(dir) > bestSize && hasSegmentsAt(parts, dir, 0) {
+			best, bestSize = c, len(dir)
+		}
+	}
+	if bestSize == 0 {
+		return Category{}, nil, false
+	}
+
+	return best, parts[bestSize:], true
+}
+
+// hasSegmentsAt reports whether parts contains dir starting at index i.
+func hasSegmentsAt(parts, dir []string, i int) bool {
+	if i+len(dir) > len(parts) {
+		return false
+	}
+	for j, segment := range dir {
+		if parts[i+j] != segment {
+			return false
 		}
 	}
-	return false
+	return true
 }
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPluginMetadata_Categories(t *testing.T) {
+	metadata := &PluginMetadata{
+		Plugins: map[string]PluginConfig{
+			"secure":   {},
+			"patterns": {},
+			"ops":      {SourceDir: "/guides/operations/"},
+		},
+	}
+
+	want := CategorySet{
+		{Name: "ops", SourceDir: "guides/operations"},
+		{Name: "patterns", SourceDir: "patterns"},
+		{Name: "secure", SourceDir: "secure"},
+	}
+	if got := metadata.Categories(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Categories() = %v, want %v", got, want)
+	}
+}
+
+func TestCategorySet_Locate(t *testing.T) {
+	set := CategorySet{
+		{Name: "ops", SourceDir: "guides/operations"},
+		{Name: "patterns", SourceDir: "patterns"},
+		{Name: "secure", SourceDir: "secure"},
+		{Name: "supply-chain", SourceDir: "secure/supply-chain"},
+	}
+
+	tests := []struct {
+		name         string
+		path         []string
+		wantCategory string
+		wantSegments []string
+		wantOK       bool
+	}{
+		{
+			name:         "category root",
+			path:         []string{"patterns", "index.md"},
+			wantCategory: "patterns",
+			wantSegments: []string{},
+			wantOK:       true,
+		},
+		{
+			name:         "nested topic",
+			path:         []string{"patterns", "architecture", "hub-and-spoke", "index.md"},
+			wantCategory: "patterns",
+			wantSegments: []string{"architecture", "hub-and-spoke"},
+			wantOK:       true,
+		},
+		{
+			name:         "multi-segment source dir",
+			path:         []string{"guides", "operations", "runbooks", "index.md"},
+			wantCategory: "ops",
+			wantSegments: []string{"runbooks"},
+			wantOK:       true,
+		},
+		{
+			name:         "deepest source dir wins",
+			path:         []string{"secure", "supply-chain", "slsa", "index.md"},
+			wantCategory: "supply-chain",
+			wantSegments: []string{"slsa"},
+			wantOK:       true,
+		},
+		{
+			name:         "source dir must start the path",
+			path:         []string{"secure", "patterns", "index.md"},
+			wantCategory: "secure",
+			wantSegments: []string{"patterns"},
+			wantOK:       true,
+		},
+		{
+			name:   "category named below the docs root",
+			path:   []string{"blog", "patterns", "index.md"},
+			wantOK: false,
+		},
+		{
+			name:   "outside every category",
+			path:   []string{"blog", "index.md"},
+			wantOK: false,
+		},
+		{
+			name:   "partial source dir",
+			path:   []string{"guides", "index.md"},
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			category, segments, ok := set.Locate(filepath.Join(tt.path...))
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if category.Name != tt.wantCategory {
+				t.Errorf("category = %q, want %q", category.Name, tt.wantCategory)
+			}
+			if !reflect.DeepEqual(segments, tt.wantSegments) {
+				t.Errorf("segments = %q, want %q", segments, tt.wantSegments)
+			}
+		})
+	}
+}
+
+func TestCategorySet_Lookup(t *testing.T) {
+	set := CategorySet{{Name: "patterns", SourceDir: "patterns"}}
+
+	if !set.Contains("patterns") {
+		t.Error("expected patterns to be in the set")
+	}
+	if set.Contains("enforce") {
+		t.Error("expected enforce not to be in the set")
+	}
+	if got := set.Names(); !reflect.DeepEqual(got, []string{"patterns"}) {
+		t.Errorf("Names() = %v", got)
+	}
+}
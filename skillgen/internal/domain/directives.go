@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// Directives are a document's own instructions to the generator, from the
+// skillgen: key in its frontmatter:
+//
+//	skillgen:
+//	  exclude: true         # leave the page out of the skills
+//	  group: architecture   # list it under another group
+//	  title: Hub and Spoke  # replace its title in the hub
+//	  description: Use when ...
+//	  weight: -1            # sort it earlier within its group
+type Directives struct {
+	Exclude bool
+
+	// Group is the slug of the group to list the page under, as its
+	// directory would name it.
+	Group string
+
+	Title       string
+	Description string
+
+	// Weight orders pages within their group and groups within the hub:
+	// lower weights come first, and pages without one weigh 0.
+	Weight int
+}
+
+// Directives reads the skillgen: frontmatter key. A document without one
+// has no directives; one that is not a mapping of the known keys with the
+// right types is an error.
+func (f Frontmatter) Directives() (Directives, error) {
+	var d Directives
+	raw, ok := f.RawData["skillgen"]
+	if !ok || raw == nil {
+		return d, nil
+	}
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return d, fmt.Errorf("skillgen frontmatter must be a mapping, got %T", raw)
+	}
+
+	for key, value := range fields {
+		var ok bool
+		switch key {
+		case "exclude":
+			d.Exclude, ok = value.(bool)
+		case "group":
+			d.Group, ok = value.(string)
+		case "title":
+			d.Title, ok = value.(string)
+		case "description":
+			d.Description, ok = value.(string)
+		case "weight":
+			d.Weight, ok = asInt(value)
+		default:
+			return Directives{}, fmt.Errorf("unknown skillgen frontmatter key %q", key)
+		}
+		if !ok {
+			return Directives{}, fmt.Errorf("invalid skillgen frontmatter %s: %v", key, value)
+		}
+	}
+
+	return d, nil
+}
+
+// asInt converts a whole number decoded from YAML (an int) or from the
+// build cache's JSON (a float64).
+func asInt(value interface{}) (int, bool) {
+	switch n := value.(type) {
+	case int:
+		return n, true
+	case int64:
+		return int(n), true
+	case uint64:
+		return int(n), true
+	case float64:
+		if n != math.Trunc(n) {
+			return 0, false
+		}
+		return int(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFrontmatter_Directives(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw         map[string]interface{}
+		want        Directives
+		errContains string
+	}{
+		{name: "none", raw: map[string]interface{}{"title": "T"}},
+		{
+			name: "from YAML",
+			raw: map[string]interface{}{"skillgen": map[string]interface{}{
+				"exclude": true, "group": "architecture", "title": "T", "description": "D", "weight": -2,
+			}},
+			want: Directives{Exclude: true, Group: "architecture", Title: "T", Description: "D", Weight: -2},
+		},
+		{
+			// The build cache stores RawData as JSON, so numbers come back
+			// as float64.
+			name: "from the build cache",
+			raw:  map[string]interface{}{"skillgen": map[string]interface{}{"weight": float64(3)}},
+			want: Directives{Weight: 3},
+		},
+		{
+			name:        "not a mapping",
+			raw:         map[string]interface{}{"skillgen": "exclude"},
+			errContains: "must be a mapping",
+		},
+		{
+			name:        "unknown key",
+			raw:         map[string]interface{}{"skillgen": map[string]interface{}{"hide": true}},
+			errContains: `unknown skillgen frontmatter key "hide"`,
+		},
+		{
+			name:        "wrong type",
+			raw:         map[string]interface{}{"skillgen": map[string]interface{}{"weight": 1.5}},
+			errContains: "invalid skillgen frontmatter weight",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Frontmatter{RawData: tt.raw}.Directives()
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("expected an error containing %q, got %v", tt.errContains, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Directives() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "sort"
+
+// DocsTree lists every file under the docs root, by slash-separated path
+// relative to it, so links between pages can be checked without reading
+// the docs again.
+type DocsTree struct {
+	files map[string]bool
+}
+
+// NewDocsTree creates a tree of the files at paths.
+func NewDocsTree(paths []string) *DocsTree {
+	t := &DocsTree{files: make(map[string]bool, len(paths))}
+	for _, p := range paths {
+		t.files[p] = true
+	}
+	return t
+}
+
+// Has reports whether the file at rel exists. A nil tree has no files.
+func (t *DocsTree) Has(rel string) bool {
+	return t != nil && t.files[rel]
+}
+
+// Paths returns every file's path, sorted. A nil tree has none.
+func (t *DocsTree) Paths() []string {
+	if t == nil {
+		return nil
+	}
+	paths := make([]string, 0, len(t.files))
+	for p := range t.files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
@@ -13,7 +13,11 @@ type Document struct {
 	Mermaid      []MermaidDiagram
 	Tables       []Table
 	Admonitions  []Admonition
+	Snippets     []Snippet  // snippet includes expanded into RawContent
+	Links        []Link     // every link and image in RawContent
+	Assets       []AssetRef // local files, other than pages, RawContent links to
 	RawContent   string
+	Lines        SourceMap // where each line of RawContent came from in the file
 	RelatedDocs  []string
 }
 
@@ -36,12 +40,13 @@ func (f Frontmatter) IsBlogPost() bool {
 // Section represents a markdown heading and its content.
 // Sections are hierarchical, with subsections nested within parent sections.
 type Section struct {
-	Title       string
-	Level       int // H1=1, H2=2, H3=3, etc.
+	Title       string // heading text as rendered, inline code included
+	ID          string // anchor MkDocs gives the heading: its { #id }, or one made from Title
+	Level       int    // H1=1, H2=2, H3=3, etc.
 	Content     string
 	SubSections []Section
-	LineStart   int
-	LineEnd     int
+	Position        // of the heading
+	EndLine     int // last line of the section's content
 }
 
 // CodeBlock represents a fenced code block in markdown.
@@ -49,35 +54,51 @@ type CodeBlock struct {
 	Language string // e.g., "bash", "yaml", "go", "json"
 	Content  string
 	Filename string // Inferred from comments or language extension
-	LineNum  int
+	Position        // of the opening fence
 }
 
 // MermaidDiagram represents a Mermaid diagram code block.
 // Mermaid diagrams in AEL docs use the Ghostty Hardcore theme colors.
 type MermaidDiagram struct {
-	Content string
-	Title   string
-	LineNum int
+	Content  string
+	Title    string
+	Position // of the opening fence
 }
 
 // Table represents a markdown table with headers and rows.
 type Table struct {
-	Headers []string
-	Rows    [][]string
-	LineNum int
+	Headers  []string
+	Rows     [][]string
+	Position // of the header row
 }
 
 // Admonition represents a Material for MkDocs admonition block.
-// These are formatted as "!!! type "title"" and need to be converted to blockquotes.
+// These are formatted as "!!! type "title"" (or "??? type" when collapsible)
+// and need to be converted to blockquotes.
 type Admonition struct {
-	Type    string // abstract, tip, warning, success, info, note, danger
-	Title   string
-	Content string
-	LineNum int
+	Type        string // abstract, tip, warning, success, info, note, danger
+	Title       string // as rendered: the type, capitalized, if untitled
+	Content     string
+	Collapsible bool
+	Position    // of the "!!!" or "???" line
+}
+
+// Link is a link or image in a document: its destination, as the markdown
+// parser decodes it, and where it is.
+type Link struct {
+	Dest string
+	Position
+}
+
+// AssetRef is a local file, other than a page, that a document links to:
+// its path, and where the first link to it is.
+type AssetRef struct {
+	Path string
+	Position
 }
 
 // DetermineCategory extracts the category from the document's file path.
-// Categories map to skill collections: patterns, enforce, build, secure.
+// Categories map to the plugin collections in plugin-metadata.json.
 func (d *Document) DetermineCategory() string {
 	// Extract category from path like "/docs/patterns/..." or "/docs/enforce/..."
 	// Implementation will be in the parser adapter
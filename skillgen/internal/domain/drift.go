@@ -0,0 +1,23 @@
+package domain
+
+// DriftKind classifies how a committed file differs from freshly generated
+// output.
+type DriftKind string
+
+const (
+	// DriftModified means the file exists on disk with different content.
+	DriftModified DriftKind = "modified"
+	// DriftMissing means generation produces the file but it is not on disk.
+	DriftMissing DriftKind = "missing"
+	// DriftUnexpected means the file is on disk inside generator-owned
+	// output but generation no longer produces it.
+	DriftUnexpected DriftKind = "unexpected"
+)
+
+// FileDrift is a single difference between the committed output and what a
+// regeneration would write.
+type FileDrift struct {
+	Path string
+	Kind DriftKind
+	Diff string // Unified diff from the committed file to the generated one
+}
@@ -0,0 +1,21 @@
+package domain
+
+// GeneratedManifest lists every file a generation run produced, so the
+// output can be checked for hand edits without the docs checkout.
+type GeneratedManifest struct {
+	// SourceCommit is the docs commit the run read, when it read the docs
+	// from git with --source-ref.
+	SourceCommit string          `json:"sourceCommit,omitempty"`
+	Files        []GeneratedFile `json:"files"`
+}
+
+// GeneratedFile is one generated file. Path is slash-separated and relative
+// to the manifest's directory. Source, when the file is built from a single
+// document, is that document's slash-separated path relative to the docs
+// root; SKILL.md and reference.md name their category's root document.
+type GeneratedFile struct {
+	Path         string `json:"path"`
+	SHA256       string `json:"sha256"`
+	Source       string `json:"source,omitempty"`
+	SourceSHA256 string `json:"sourceSha256,omitempty"`
+}
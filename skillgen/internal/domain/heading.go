@@ -0,0 +1,132 @@
+package domain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// idCount matches an id that already ends in a repeat count: "setup_1".
+var idCount = regexp.MustCompile(`^(.*)_([0-9]+)$`)
+
+// HeadingID returns the id MkDocs gives a heading with text, as its toc
+// extension slugifies it: folded to ASCII, everything but word characters,
+// spaces, and hyphens dropped, lowercased, and each run of spaces and
+// hyphens turned to one hyphen.
+// Example: "Use `skillgen.yaml` (v2)" -> "use-skillgenyaml-v2"
+func HeadingID(text string) string {
+	var kept strings.Builder
+	for _, r := range asciiFold(text) {
+		if r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsSpace(r) {
+			kept.WriteRune(r)
+		}
+	}
+
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(kept.String())) {
+		if r == '-' || unicode.IsSpace(r) {
+			dash = true
+			continue
+		}
+		if dash {
+			b.WriteRune('-')
+			dash = false
+		}
+		b.WriteRune(r)
+	}
+	if dash {
+		b.WriteRune('-')
+	}
+	return b.String()
+}
+
+// asciiFolds maps each character whose NFKD decomposition has ASCII in it
+// to that ASCII: a Latin letter with diacritics to its base letter, and a
+// compatibility character such as a ligature or superscript to what it
+// stands for. Characters missing here that NFKD leaves non-ASCII, such as
+// "ß" or "ø", have no ASCII form and are dropped.
+var asciiFolds = foldTable(
+	"ÀÁÂÃÄÅĀĂĄ", "A", "àáâãäåāăąª", "a",
+	"ÇĆĈĊČ", "C", "çćĉċč", "c",
+	"Ď", "D", "ď", "d",
+	"ÈÉÊËĒĔĖĘĚ", "E", "èéêëēĕėęě", "e",
+	"ĜĞĠĢ", "G", "ĝğġģ", "g",
+	"Ĥ", "H", "ĥ", "h",
+	"ÌÍÎÏĨĪĬĮİ", "I", "ìíîïĩīĭį", "i",
+	"Ĵ", "J", "ĵ", "j",
+	"Ķ", "K", "ķ", "k",
+	"ĹĻĽĿ", "L", "ĺļľŀ", "l",
+	"ÑŃŅŇ", "N", "ñńņňŉ", "n",
+	"ÒÓÔÕÖŌŎŐ", "O", "òóôõöōŏőº", "o",
+	"ŔŖŘ", "R", "ŕŗř", "r",
+	"ŚŜŞŠ", "S", "śŝşšſ", "s",
+	"ŢŤ", "T", "ţť", "t",
+	"ÙÚÛÜŨŪŬŮŰŲ", "U", "ùúûüũūŭůűų", "u",
+	"Ŵ", "W", "ŵ", "w",
+	"ÝŶŸ", "Y", "ýÿŷ", "y",
+	"ŹŻŽ", "Z", "źżž", "z",
+	"Ĳ", "IJ", "ĳ", "ij",
+	"ﬀ", "ff", "ﬁ", "fi", "ﬂ", "fl", "ﬃ", "ffi", "ﬄ", "ffl", "ﬅﬆ", "st",
+	"¹", "1", "²", "2", "³", "3", "¼", "14", "½", "12", "¾", "34",
+	"\u00a0", " ",
+)
+
+// foldTable builds asciiFolds from pairs of the characters to fold and
+// the ASCII they fold to.
+func foldTable(pairs ...string) map[rune]string {
+	table := make(map[rune]string)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		for _, r := range pairs[i] {
+			table[r] = pairs[i+1]
+		}
+	}
+	return table
+}
+
+// asciiFold returns text with every character in the ASCII it decomposes
+// to under NFKD and everything non-ASCII dropped, as Python-Markdown's
+// slugify does, so "Café" becomes "Cafe". Combining marks are non-ASCII,
+// so a letter written as base plus accent folds the same way.
+func asciiFold(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case r >= '！' && r <= '～':
+			// Fullwidth forms decompose to their ASCII counterparts.
+			b.WriteRune(r - '！' + '!')
+		default:
+			b.WriteString(asciiFolds[r])
+		}
+	}
+	return b.String()
+}
+
+// HeadingIDs tracks the ids used on one page, so each heading gets its own
+// as MkDocs gives them: a repeat of an id, or an empty one, has "_1", "_2",
+// and so on added.
+type HeadingIDs map[string]bool
+
+// Reserve marks id as used without changing it, for an id the page sets
+// itself, such as with "{ #id }".
+func (ids HeadingIDs) Reserve(id string) {
+	ids[id] = true
+}
+
+// Unique returns id, or the next unused id made from it, and marks it used.
+func (ids HeadingIDs) Unique(id string) string {
+	for id == "" || ids[id] {
+		if m := idCount.FindStringSubmatch(id); m != nil {
+			n, _ := strconv.Atoi(m[2])
+			id = m[1] + "_" + strconv.Itoa(n+1)
+		} else {
+			id += "_1"
+		}
+	}
+	ids[id] = true
+	return id
+}
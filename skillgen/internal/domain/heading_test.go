@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+func TestHeadingID(t *testing.T) {
+	tests := []struct {
+		text, want string
+	}{
+		{"Getting Started", "getting-started"},
+		{"Use skillgen.yaml (v2)", "use-skillgenyaml-v2"},
+		{"Hub - and -- Spoke", "hub-and-spoke"},
+		{"snake_case stays", "snake_case-stays"},
+		{"Café crème", "cafe-creme"},
+		{"Übersicht für Größe", "ubersicht-fur-groe"},
+		{"Cafe\u0301 decomposed", "cafe-decomposed"},
+		{"ﬁnal ２", "final-2"},
+		{"日本語 docs", "docs"},
+		{"- leading and trailing -", "-leading-and-trailing-"},
+		{"!!!", ""},
+	}
+
+	for _, tt := range tests {
+		if got := HeadingID(tt.text); got != tt.want {
+			t.Errorf("HeadingID(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestHeadingIDs_Unique(t *testing.T) {
+	ids := make(HeadingIDs)
+	ids.Reserve("custom")
+
+	for _, tt := range []struct{ id, want string }{
+		{"setup", "setup"},
+		{"setup", "setup_1"},
+		{"setup_1", "setup_2"},
+		{"custom", "custom_1"},
+		{"", "_1"},
+	} {
+		if got := ids.Unique(tt.id); got != tt.want {
+			t.Errorf("Unique(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
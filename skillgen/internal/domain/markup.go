@@ -0,0 +1,97 @@
+package domain
+
+import "fmt"
+
+// MarkupConstruct is an MkDocs-only markup construct that plain markdown
+// has no syntax for.
+type MarkupConstruct string
+
+const (
+	// MarkupAttrList is an attr_list suffix, such as "{ .md-button }" or
+	// "{ #anchor }".
+	MarkupAttrList MarkupConstruct = "attrList"
+
+	// MarkupEmoji is a Material emoji or icon shortcode, such as
+	// ":material-check:" or ":rocket:".
+	MarkupEmoji MarkupConstruct = "emoji"
+
+	// MarkupAnnotations is a code annotation marker, such as "# (1)!", with
+	// the ordered list after the code block that holds the annotations.
+	MarkupAnnotations MarkupConstruct = "annotations"
+
+	// MarkupMacros is an mkdocs-macros "{{ ... }}" expression.
+	MarkupMacros MarkupConstruct = "macros"
+)
+
+// MarkupAction is what the markup pass does with a construct.
+type MarkupAction string
+
+const (
+	// MarkupStrip removes the construct.
+	MarkupStrip MarkupAction = "strip"
+
+	// MarkupPlain replaces the construct with its plain markdown
+	// equivalent: an icon or emoji with its Unicode character, an
+	// annotation marker with a "(1)" the list after the block still
+	// answers. attr_list and macros have none, so it strips them.
+	MarkupPlain MarkupAction = "plain"
+
+	// MarkupFlag leaves the construct as written and reports it.
+	MarkupFlag MarkupAction = "flag"
+)
+
+// MarkupRules choose the action for each construct. An empty field uses
+// that construct's default: attr_list is stripped, emoji and annotations
+// are made plain, and macros are flagged, since their value is unknown.
+type MarkupRules struct {
+	AttrList    MarkupAction `json:"attrList,omitempty"`
+	Emoji       MarkupAction `json:"emoji,omitempty"`
+	Annotations MarkupAction `json:"annotations,omitempty"`
+	Macros      MarkupAction `json:"macros,omitempty"`
+}
+
+// Action returns the action for construct c.
+func (r MarkupRules) Action(c MarkupConstruct) MarkupAction {
+	action, fallback := r.AttrList, MarkupStrip
+	switch c {
+	case MarkupEmoji:
+		action, fallback = r.Emoji, MarkupPlain
+	case MarkupAnnotations:
+		action, fallback = r.Annotations, MarkupPlain
+	case MarkupMacros:
+		action, fallback = r.Macros, MarkupFlag
+	}
+	if action == "" {
+		return fallback
+	}
+	return action
+}
+
+// Validate reports the first rule that is not a known action.
+func (r MarkupRules) Validate() error {
+	rules := []struct {
+		construct MarkupConstruct
+		action    MarkupAction
+	}{
+		{MarkupAttrList, r.AttrList},
+		{MarkupEmoji, r.Emoji},
+		{MarkupAnnotations, r.Annotations},
+		{MarkupMacros, r.Macros},
+	}
+	for _, rule := range rules {
+		switch rule.action {
+		case "", MarkupStrip, MarkupPlain, MarkupFlag:
+		default:
+			return fmt.Errorf("markup %s %q must be %q, %q, or %q", rule.construct, rule.action, MarkupStrip, MarkupPlain, MarkupFlag)
+		}
+	}
+	return nil
+}
+
+// FlaggedMarkup is a construct left in a generated file because its rule is
+// MarkupFlag.
+type FlaggedMarkup struct {
+	Construct MarkupConstruct
+	Text      string
+	Position  // in the doc's file
+}
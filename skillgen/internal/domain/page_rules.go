@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// IgnoreFileName is the file in a category's source directory that lists
+// more exclude patterns, one per line, like a .gitignore.
+const IgnoreFileName = ".skillgenignore"
+
+// PageRules select which pages under a category's source directory become
+// documents. Patterns are slash-separated globs relative to the source
+// directory, matched as in a .gitignore: "*", "?", and "[...]" match within
+// a path segment, "**" matches any number of segments, a pattern with no
+// slash except a trailing one matches a name at any depth, a leading "/"
+// anchors a pattern to the source directory, and a trailing "/" matches
+// everything under a directory.
+type PageRules struct {
+	// Include, if not empty, keeps only the pages matching one of its
+	// patterns.
+	Include []string
+
+	// Exclude drops the pages matching its patterns. They apply in order
+	// and the last match wins, so a later "!pattern" brings back a page an
+	// earlier pattern dropped.
+	Exclude []string
+}
+
+// Allows reports whether the page at rel, relative to the source
+// directory, is selected.
+func (r PageRules) Allows(rel string) bool {
+	if len(r.Include) > 0 {
+		included := false
+		for _, pattern := range r.Include {
+			if MatchGlob(pattern, rel) {
+				included = true
+				break
+			}
+		}
+		if !included {
+			return false
+		}
+	}
+
+	allowed := true
+	for _, pattern := range r.Exclude {
+		negated := strings.HasPrefix(pattern, "!")
+		if MatchGlob(strings.TrimPrefix(pattern, "!"), rel) {
+			allowed = negated
+		}
+	}
+	return allowed
+}
+
+// Validate returns an error for the first malformed pattern.
+func (r PageRules) Validate() error {
+	for _, pattern := range append(append([]string(nil), r.Include...), r.Exclude...) {
+		for _, segment := range globSegments(strings.TrimPrefix(pattern, "!")) {
+			if _, err := path.Match(segment, ""); err != nil {
+				return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ParseIgnoreFile returns the patterns in a .skillgenignore: every line
+// that is neither blank nor a "#" comment.
+func ParseIgnoreFile(content string) []string {
+	var patterns []string
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns
+}
+
+// MatchGlob reports whether name, a slash-separated path, matches pattern,
+// with PageRules' pattern syntax. A malformed pattern matches nothing.
+func MatchGlob(pattern, name string) bool {
+	return matchSegments(globSegments(pattern), strings.Split(name, "/"))
+}
+
+// globSegments splits pattern into segments, anchoring it the way a
+// .gitignore does.
+func globSegments(pattern string) []string {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	if !strings.Contains(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	if dirOnly {
+		pattern += "/**"
+	}
+	return strings.Split(pattern, "/")
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches zero or more path segments.
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"drafts/**", "drafts/a.md", true},
+		{"drafts/**", "drafts/x/y/index.md", true},
+		{"drafts/**", "ops/drafts/a.md", false},
+		{"drafts/", "ops/drafts/a.md", true},
+		{"/drafts/", "ops/drafts/a.md", false},
+		{"_*.md", "a/b/_private.md", true},
+		{"_*.md", "a/b/public.md", false},
+		{"a/*/index.md", "a/b/index.md", true},
+		{"a/*/index.md", "a/b/c/index.md", false},
+		{"a/**/index.md", "a/index.md", true},
+		{"a/**/index.md", "a/b/c/index.md", true},
+		{"[bad", "bad", false},
+	}
+
+	for _, tt := range tests {
+		if got := MatchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPageRules_Allows(t *testing.T) {
+	rules := PageRules{
+		Include: []string{"guides/**", "/index.md"},
+		Exclude: []string{"drafts/", "!guides/drafts/keep.md"},
+	}
+
+	tests := map[string]bool{
+		"index.md":               true,
+		"guides/a.md":            true,
+		"guides/drafts/wip.md":   false,
+		"guides/drafts/keep.md":  true,
+		"reference/api/index.md": false,
+	}
+	for page, want := range tests {
+		if got := rules.Allows(page); got != want {
+			t.Errorf("Allows(%q) = %v, want %v", page, got, want)
+		}
+	}
+
+	if !(PageRules{}).Allows("anything.md") {
+		t.Error("expected empty rules to allow every page")
+	}
+}
+
+func TestPageRules_Validate(t *testing.T) {
+	if err := (PageRules{Include: []string{"a/**"}, Exclude: []string{"!b/*.md"}}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (PageRules{Exclude: []string{"a/[b"}}).Validate(); err == nil {
+		t.Error("expected an error for a malformed pattern")
+	}
+}
+
+func TestParseIgnoreFile(t *testing.T) {
+	content := "# drafts stay private\ndrafts/\n\n  _*.md  \n!drafts/keep.md\n"
+	want := []string{"drafts/", "_*.md", "!drafts/keep.md"}
+	if got := ParseIgnoreFile(content); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseIgnoreFile() = %v, want %v", got, want)
+	}
+}
@@ -31,12 +31,52 @@ type PluginConfig struct {
 	// If empty, uses the plugin key from the map.
 	MarketplaceName string `json:"marketplaceName,omitempty"`
 
+	// SourceDir is the docs directory, relative to the docs root, that the
+	// collection's hub is built from. If empty, uses the plugin key.
+	SourceDir string `json:"sourceDir,omitempty"`
+
+	// Include and Exclude select the pages under SourceDir the hub is
+	// built from; see PageRules. A .skillgenignore in SourceDir adds more
+	// Exclude patterns.
+	Include []string `json:"include,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+
+	// AdmonitionStyle is how the hub renders admonitions. If empty, uses
+	// AdmonitionBlockquote.
+	AdmonitionStyle AdmonitionStyle `json:"admonitionStyle,omitempty"`
+
+	// Markup chooses what happens to MkDocs-only markup, such as attr_list
+	// and emoji shortcodes, in the hub; see MarkupRules.
+	Markup MarkupRules `json:"markup,omitzero"`
+
+	// Assets choose which local files the hub's docs link to are bundled
+	// into its library/; see AssetRules.
+	Assets AssetRules `json:"assets,omitzero"`
+
 	Description string   `json:"description"`
 	Category    string   `json:"category"`
 	Tags        []string `json:"tags"`
 	Keywords    []string `json:"keywords"`
 }
 
+// AdmonitionStyle is how admonitions are rendered in generated markdown.
+type AdmonitionStyle string
+
+const (
+	// AdmonitionBlockquote renders an admonition as a blockquote led by its
+	// bold title, dropping its type.
+	AdmonitionBlockquote AdmonitionStyle = "blockquote"
+
+	// AdmonitionGitHub renders an admonition as a GitHub alert, such as
+	// "> [!WARNING]", keeping its type and title.
+	AdmonitionGitHub AdmonitionStyle = "github"
+)
+
+// Valid reports whether s is a known style, or empty for the default.
+func (s AdmonitionStyle) Valid() bool {
+	return s == "" || s == AdmonitionBlockquote || s == AdmonitionGitHub
+}
+
 // PluginManifest represents an individual plugin.json file.
 // This is what gets written to skills/{collection}/.claude-plugin/plugin.json
 type PluginManifest struct {
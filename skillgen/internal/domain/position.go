@@ -0,0 +1,45 @@
+package domain
+
+// Position is a place in a file: a 1-based line, and a 1-based column
+// counted in characters. A zero Line is an unknown position, and a zero
+// Column an unknown place on a known line.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// SourceLine is where a line of a document's markdown came from: the
+// 1-based line of the document's file it was read from, or, if Included,
+// the line of the snippet include that pulled it in.
+type SourceLine struct {
+	Line     int
+	Included bool
+}
+
+// SourceMap maps each line of a document's markdown, as parsed, by 0-based
+// index, back to its file. Parsing sees the markdown with frontmatter cut
+// and snippets expanded, so its lines are not the file's.
+type SourceMap []SourceLine
+
+// Locate returns the position in the file of p, a position in the parsed
+// markdown. A place on a line a snippet include pulled in is located at the
+// start of the include. A map that does not cover p, such as a nil one,
+// leaves it as it is.
+func (m SourceMap) Locate(p Position) Position {
+	if p.Line < 1 || p.Line > len(m) {
+		return p
+	}
+	source := m[p.Line-1]
+	if source.Included {
+		return Position{Line: source.Line, Column: 1}
+	}
+	return Position{Line: source.Line, Column: p.Column}
+}
+
+// Shift moves every line in m down by n, for markdown that starts n lines
+// into its file.
+func (m SourceMap) Shift(n int) {
+	for i := range m {
+		m[i].Line += n
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import "testing"
+
+func TestSourceMap_Locate(t *testing.T) {
+	m := SourceMap{{Line: 1}, {Line: 2}, {Line: 4, Included: true}, {Line: 4, Included: true}, {Line: 5}}
+	m.Shift(3)
+
+	tests := []struct {
+		in, want Position
+	}{
+		{Position{Line: 2, Column: 7}, Position{Line: 5, Column: 7}},
+		{Position{Line: 4, Column: 3}, Position{Line: 7, Column: 1}},
+		{Position{Line: 5}, Position{Line: 8}},
+		{Position{}, Position{}},
+		{Position{Line: 9, Column: 2}, Position{Line: 9, Column: 2}},
+	}
+
+	for _, tt := range tests {
+		if got := m.Locate(tt.in); got != tt.want {
+			t.Errorf("Locate(%+v) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+
+	if got := SourceMap(nil).Locate(Position{Line: 3, Column: 1}); got != (Position{Line: 3, Column: 1}) {
+		t.Errorf("nil Locate = %+v, want the position unchanged", got)
+	}
+}
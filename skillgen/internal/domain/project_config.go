@@ -0,0 +1,45 @@
+package domain
+
+// ProjectConfig represents skillgen.yaml: the settings that would otherwise
+// be passed as flags on every run. Every field is optional; an empty value
+// leaves the flag default in place.
+type ProjectConfig struct {
+	Paths      ProjectPaths    `yaml:"paths"`
+	Jobs       int             `yaml:"jobs"`
+	Verbose    *bool           `yaml:"verbose"`
+	Categories []string        `yaml:"categories"`
+	BaseURLs   ProjectBaseURLs `yaml:"base_urls"`
+	Output     ProjectOutput   `yaml:"output"`
+}
+
+// ProjectPaths locates the docs source, templates, config files, and
+// generated output. Relative paths are relative to skillgen.yaml itself.
+type ProjectPaths struct {
+	Source          string `yaml:"source"`
+	Output          string `yaml:"output"`
+	Marketplace     string `yaml:"marketplace"`
+	Readme          string `yaml:"readme"`
+	Templates       string `yaml:"templates"`
+	PluginMetadata  string `yaml:"plugin_metadata"`
+	ReleaseManifest string `yaml:"release_manifest"`
+	Cache           string `yaml:"cache"`
+	Manifest        string `yaml:"manifest"`
+	MkDocsConfig    string `yaml:"mkdocs_config"`
+
+	// Snippets are the directories --8<-- snippet includes are looked up
+	// in, in order.
+	Snippets []string `yaml:"snippets"`
+}
+
+// ProjectBaseURLs are the sites generated links point at.
+type ProjectBaseURLs struct {
+	// Docs is the published docs site that topic, group, and hub source
+	// links are built from.
+	Docs string `yaml:"docs"`
+}
+
+// ProjectOutput controls how a run reports its results.
+type ProjectOutput struct {
+	// FailOn is the --fail-on policy: never, error, or warning.
+	FailOn string `yaml:"fail_on"`
+}
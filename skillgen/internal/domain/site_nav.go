@@ -0,0 +1,64 @@
+package domain
+
+import "strings"
+
+// SiteNav is the docs site's curated navigation, read from mkdocs.yml's
+// nav: tree. It decides which pages become topics, in what order, and
+// under which titles.
+type SiteNav struct {
+	// Pages lists every local page the nav links to, depth-first in nav
+	// order.
+	Pages []NavPage
+
+	positions map[string]int
+}
+
+// NavPage is one page entry in the nav. Path is slash-separated and relative
+// to the docs root, as mkdocs.yml writes it. Title is the nav's title for
+// the page, or "" if the nav leaves the title to the page itself. Line is
+// the entry's line in mkdocs.yml, for findings.
+type NavPage struct {
+	Path  string `json:"path"`
+	Title string `json:"title,omitempty"`
+	Line  int    `json:"-"`
+}
+
+// NewSiteNav creates a nav from its pages in nav order. A page listed twice
+// keeps its first position.
+func NewSiteNav(pages []NavPage) *SiteNav {
+	positions := make(map[string]int, len(pages))
+	for i, page := range pages {
+		if _, ok := positions[page.Path]; !ok {
+			positions[page.Path] = i
+		}
+	}
+	return &SiteNav{Pages: pages, positions: positions}
+}
+
+// Lookup finds the page at path, relative to the docs root, and returns its
+// position in the nav. A nil nav contains nothing.
+func (n *SiteNav) Lookup(path string) (NavPage, int, bool) {
+	if n == nil {
+		return NavPage{}, 0, false
+	}
+	i, ok := n.positions[path]
+	if !ok {
+		return NavPage{}, 0, false
+	}
+	return n.Pages[i], i, true
+}
+
+// Under returns the pages under dir, a slash-separated directory relative
+// to the docs root, in nav order.
+func (n *SiteNav) Under(dir string) []NavPage {
+	if n == nil {
+		return nil
+	}
+	var pages []NavPage
+	for _, page := range n.Pages {
+		if strings.HasPrefix(page.Path, dir+"/") {
+			pages = append(pages, page)
+		}
+	}
+	return pages
+}
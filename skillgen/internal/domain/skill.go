@@ -6,8 +6,9 @@ package domain
 type Skill struct {
 	Metadata     SkillMetadata
 	Groups       []TopicGroup
-	LibraryFiles []LibraryFile // Every source doc, verbatim, mirroring the docs tree
-	MainContent  string        // SKILL.md content (required)
+	LibraryFiles []LibraryFile  // Every source doc, verbatim, mirroring the docs tree
+	Assets       []LibraryAsset // Local files the docs link to, bundled beside them
+	MainContent  string         // SKILL.md content (required)
 }
 
 // SkillMetadata contains the frontmatter and derived metadata for a hub skill.
@@ -30,6 +31,7 @@ type TopicGroup struct {
 	Description   string // One-line group blurb
 	URL           string // Upstream URL to the group's own section page, if any
 	ReferenceBody string // Full cleaned body of the group's own doc, if any
+	SourcePath    string // Path of the group's own doc, if any
 	Topics        []Topic
 }
 
@@ -44,6 +46,7 @@ type Topic struct {
 	URL           string
 	LibraryPath   string // Path to the library/ file, relative to SKILL.md
 	ReferenceBody string // Full cleaned body of the topic's doc, for reference.md
+	SourcePath    string // Original document path
 }
 
 // LibraryFile is a single source doc shipped verbatim (title, source URL
@@ -52,8 +55,13 @@ type Topic struct {
 // under the category. This is the complete unmerged source library,
 // shipped in addition to the curated reference.md.
 type LibraryFile struct {
-	RelPath string // Path relative to the hub's library/ directory
-	Content string
+	RelPath     string // Path relative to the hub's library/ directory
+	Content     string
+	SourcePath  string          // Original document path
+	Flagged     []FlaggedMarkup // MkDocs-only markup left in Content, to report
+	BrokenLinks []Link          // Links, as written, that resolve to no file
+	Skipped     []SkippedAsset  // Assets the doc links to that were not bundled
+	SameURL     string          // index.md the site serves at the doc's URL too, if any
 }
 
 // Note: CodeBlock, Table, and MermaidDiagram are defined in document.go
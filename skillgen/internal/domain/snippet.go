@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Snippet is one pymdownx snippet include ("--8<--") a document was read
+// with, resolved or not.
+type Snippet struct {
+	Target  string // as written, e.g. "examples/ci.yaml:5:12"
+	Path    string // the file it resolved to; empty if none was found
+	Line    int    // 1-based line of the include in the document
+	Problem string // why it could not be included, if it could not
+}
+
+// SnippetTarget is a parsed snippet include target: a file, optionally
+// narrowed to line ranges ("ci.yaml:5:12", "ci.yaml:1:3,8:9") or to a
+// named section ("ci.yaml:setup").
+type SnippetTarget struct {
+	Path    string
+	Ranges  []LineRange
+	Section string
+}
+
+// LineRange is a 1-based, inclusive range of lines. A zero Start means the
+// first line, and a zero End the last.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// snippetTargetSuffix splits a target into its path and either its line
+// ranges or its section name.
+var snippetTargetSuffix = regexp.MustCompile(`^(.*?)(?:((?::[0-9]*){1,2}(?:,[0-9]*(?::[0-9]*)?)*)|:([A-Za-z][-_0-9A-Za-z]*))?$`)
+
+// snippetSection matches a section marker in a snippet file, usually inside
+// a comment: "# --8<-- [start:setup]" ... "# --8<-- [end:setup]". Group 1
+// is any escaping ";", 2 the marker type, and 3 the section name.
+var snippetSection = regexp.MustCompile(`(?i)(;*)-+8<-+[ \t]+\[[ \t]*(start|end)[ \t]*:[ \t]*([a-z][-_0-9a-z]*)[ \t]*\]`)
+
+// ParseSnippetTarget parses a snippet include target as pymdownx does.
+func ParseSnippetTarget(target string) SnippetTarget {
+	match := snippetTargetSuffix.FindStringSubmatch(target)
+	t := SnippetTarget{Path: match[1], Section: match[3]}
+
+	if ranges := strings.TrimPrefix(match[2], ":"); match[2] != "" {
+		for _, span := range strings.Split(ranges, ",") {
+			bounds := strings.SplitN(span, ":", 2)
+			var r LineRange
+			r.Start, _ = strconv.Atoi(bounds[0])
+			if len(bounds) > 1 {
+				r.End, _ = strconv.Atoi(bounds[1])
+			}
+			t.Ranges = append(t.Ranges, r)
+		}
+	}
+
+	return t
+}
+
+// Select returns the lines of a snippet file the target names: the named
+// section, the line ranges, or the whole file. Section markers are never
+// part of the result.
+func (t SnippetTarget) Select(lines []string) ([]string, error) {
+	switch {
+	case t.Section != "":
+		var selected []string
+		inside, found := false, false
+		for _, line := range lines {
+			match := snippetSection.FindStringSubmatch(line)
+			if match != nil && match[1] == "" && strings.EqualFold(match[3], t.Section) {
+				if strings.EqualFold(match[2], "start") {
+					inside, found = true, true
+				} else if inside {
+					break
+				}
+				continue
+			}
+			if inside {
+				selected = append(selected, line)
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("section %q not found", t.Section)
+		}
+		return stripSectionMarkers(selected), nil
+
+	case len(t.Ranges) > 0:
+		var selected []string
+		for _, r := range t.Ranges {
+			start, end := max(r.Start, 1), r.End
+			if end == 0 || end > len(lines) {
+				end = len(lines)
+			}
+			if start <= end {
+				selected = append(selected, lines[start-1:end]...)
+			}
+		}
+		return stripSectionMarkers(selected), nil
+
+	default:
+		return stripSectionMarkers(lines), nil
+	}
+}
+
+// stripSectionMarkers drops every section marker line, and unescapes an
+// escaped one (";--8<-- [start:name]") so it shows as written.
+func stripSectionMarkers(lines []string) []string {
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		loc := snippetSection.FindStringSubmatchIndex(line)
+		switch {
+		case loc == nil:
+			kept = append(kept, line)
+		case loc[3] > loc[2]:
+			kept = append(kept, line[:loc[2]]+line[loc[2]+1:])
+		}
+	}
+	return kept
+}
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSnippetTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		want   SnippetTarget
+	}{
+		{"examples/ci.yaml", SnippetTarget{Path: "examples/ci.yaml"}},
+		{"ci.yaml:5:12", SnippetTarget{Path: "ci.yaml", Ranges: []LineRange{{5, 12}}}},
+		{"ci.yaml:3", SnippetTarget{Path: "ci.yaml", Ranges: []LineRange{{3, 0}}}},
+		{"ci.yaml::4,7:8", SnippetTarget{Path: "ci.yaml", Ranges: []LineRange{{0, 4}, {7, 8}}}},
+		{"ci.yaml:setup-job", SnippetTarget{Path: "ci.yaml", Section: "setup-job"}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseSnippetTarget(tt.target); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSnippetTarget(%q) = %+v, want %+v", tt.target, got, tt.want)
+		}
+	}
+}
+
+func TestSnippetTarget_Select(t *testing.T) {
+	lines := []string{
+		"one",
+		"# --8<-- [start:mid]",
+		"two",
+		"# ;--8<-- [start:escaped]",
+		"# --8<-- [end:mid]",
+		"three",
+	}
+
+	tests := []struct {
+		target string
+		want   []string
+	}{
+		{"f", []string{"one", "two", "# --8<-- [start:escaped]", "three"}},
+		{"f:mid", []string{"two", "# --8<-- [start:escaped]"}},
+		{"f:1:3", []string{"one", "two"}},
+		{"f:6,1:1", []string{"three", "one"}},
+		{"f:9", nil},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSnippetTarget(tt.target).Select(lines)
+		if err != nil {
+			t.Errorf("Select(%q) error: %v", tt.target, err)
+		}
+		if len(got) != len(tt.want) || (len(got) > 0 && !reflect.DeepEqual(got, tt.want)) {
+			t.Errorf("Select(%q) = %q, want %q", tt.target, got, tt.want)
+		}
+	}
+
+	if _, err := ParseSnippetTarget("f:missing").Select(lines); err == nil {
+		t.Error("Select with an unknown section: expected an error")
+	}
+}
@@ -0,0 +1,17 @@
+package domain
+
+// HubStats summarises the size of one generated hub skill, so it is easy to
+// see which hubs are approaching SKILL.md's word budget or growing the
+// bundled library fastest.
+type HubStats struct {
+	Category       string
+	Documents      int // Source docs bundled under library/
+	Groups         int
+	Topics         int
+	SkillWords     int
+	SkillBytes     int
+	ReferenceWords int
+	ReferenceBytes int
+	LibraryWords   int
+	LibraryBytes   int
+}
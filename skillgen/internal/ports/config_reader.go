@@ -12,3 +12,17 @@ type ConfigReader interface {
 	// Returns a map of path -> version (e.g., "skills/patterns" -> "0.2.1").
 	ReadReleaseManifest(path string) (map[string]string, error)
 }
+
+// ProjectConfigReader reads the project-level skillgen.yaml.
+type ProjectConfigReader interface {
+	// ReadProjectConfig reads and parses skillgen.yaml. Unknown keys are
+	// an error, so a typo can't silently fall back to a default.
+	ReadProjectConfig(path string) (*domain.ProjectConfig, error)
+}
+
+// SiteNavReader reads the docs site's navigation.
+type SiteNavReader interface {
+	// ReadSiteNav reads the nav: tree of an mkdocs.yml. Entries that are
+	// not local pages, such as external links, are left out.
+	ReadSiteNav(path string) (*domain.SiteNav, error)
+}
@@ -9,32 +9,78 @@ type FrontmatterParser interface {
 	Parse(content []byte) (*domain.Frontmatter, string, error)
 }
 
+// MarkdownParser parses a document's markdown once, into a tree that the
+// section parser and every content extractor then share, so they all see
+// the same document.
+type MarkdownParser interface {
+	// Parse parses markdown, with GitHub Flavored Markdown's tables,
+	// strikethrough, autolinks, and task lists.
+	Parse(markdown string) ParsedMarkdown
+}
+
+// ParsedMarkdown is markdown parsed by a MarkdownParser. Its syntax tree is
+// private to the adapter that built it.
+type ParsedMarkdown interface {
+	// Source returns the markdown that was parsed.
+	Source() string
+}
+
 // SectionParser parses markdown content into hierarchical sections.
 type SectionParser interface {
-	// Parse parses markdown and returns a tree of sections.
-	Parse(markdown string) ([]domain.Section, error)
+	// Parse returns the document's tree of sections.
+	Parse(doc ParsedMarkdown) ([]domain.Section, error)
 
 	// ExtractIntroduction extracts content before the first heading (after title).
-	ExtractIntroduction(markdown string) string
+	ExtractIntroduction(doc ParsedMarkdown) string
 }
 
 // ContentExtractor extracts specific types of content from markdown.
 type ContentExtractor interface {
 	// ExtractCodeBlocks finds all fenced code blocks in the markdown.
-	ExtractCodeBlocks(content string) []domain.CodeBlock
+	ExtractCodeBlocks(doc ParsedMarkdown) []domain.CodeBlock
 
 	// ExtractMermaid finds all Mermaid diagram blocks.
-	ExtractMermaid(content string) []domain.MermaidDiagram
+	ExtractMermaid(doc ParsedMarkdown) []domain.MermaidDiagram
 
 	// ExtractTables finds all markdown tables.
-	ExtractTables(content string) []domain.Table
+	ExtractTables(doc ParsedMarkdown) []domain.Table
 
 	// ExtractAdmonitions finds all Material for MkDocs admonition blocks.
-	ExtractAdmonitions(content string) []domain.Admonition
+	ExtractAdmonitions(doc ParsedMarkdown) []domain.Admonition
+
+	// ExtractLinks finds every link and image.
+	ExtractLinks(doc ParsedMarkdown) []domain.Link
 }
 
 // AdmonitionConverter converts MkDocs admonitions to standard markdown blockquotes.
 type AdmonitionConverter interface {
-	// Convert transforms "!!! type 'title'" syntax to "> **title**" blockquotes.
+	// Convert transforms "!!! type 'title'" syntax, and the rest of the
+	// Material admonition grammar (??? and ???+ details, untitled blocks,
+	// extra classes, nesting), to "> **title**" blockquotes, or, in the
+	// GitHub style, to "> [!WARNING]" alerts.
+	Convert(content string, style domain.AdmonitionStyle) string
+}
+
+// MarkupNormalizer removes or translates MkDocs-only markup that plain
+// markdown has no syntax for.
+type MarkupNormalizer interface {
+	// Normalize applies rules to the attr_list suffixes, emoji and icon
+	// shortcodes, code annotations, and mkdocs-macros expressions in
+	// content, and returns the result along with every construct a
+	// MarkupFlag rule left in place.
+	Normalize(content string, rules domain.MarkupRules) (string, []domain.FlaggedMarkup)
+}
+
+// TabConverter converts pymdownx content tabs to standard markdown.
+type TabConverter interface {
+	// Convert replaces each '=== "Tab title"' block with its title in
+	// bold followed by its de-indented body.
 	Convert(content string) string
 }
+
+// LinkRewriter rewrites the destinations of the links in markdown.
+type LinkRewriter interface {
+	// Rewrite calls fn with the destination of every link and image in
+	// content, as written, and replaces it with what fn returns.
+	Rewrite(content string, fn func(dest string) string) string
+}
@@ -1,6 +1,10 @@
 package ports
 
-import "github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+import (
+	"io/fs"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
 
 // DocumentReader reads and parses documentation files from the filesystem.
 // This interface abstracts file I/O to enable testing without real files.
@@ -8,9 +12,21 @@ type DocumentReader interface {
 	// ReadDocument reads and parses a single document file.
 	ReadDocument(path string) (*domain.Document, error)
 
-	// ListIndexFiles finds all index.md files in the specified root path.
-	// Returns absolute paths to each index.md file found.
-	ListIndexFiles(rootPath string, categories []string) ([]string, error)
+	// ListDocuments finds all markdown pages under each category's source
+	// directory in the specified root path: every index.md and every
+	// sibling page beside it.
+	// Returns each page's path under rootPath, absolute if rootPath is.
+	ListDocuments(rootPath string, categories domain.CategorySet) ([]string, error)
+}
+
+// SnippetResolver expands pymdownx snippet includes ("--8<--") in markdown.
+type SnippetResolver interface {
+	// Resolve replaces each include in content with the lines it names,
+	// nested includes too, and returns the result along with every include
+	// it found, and the line of content each line of the result came from.
+	// One that could not be included is left out of the content and
+	// returned with its Problem set.
+	Resolve(content string) (string, []domain.Snippet, domain.SourceMap)
 }
 
 // FileSystem abstracts file system operations for testing.
@@ -36,4 +52,10 @@ type FileSystem interface {
 
 	// RemoveAll removes path and any children it contains.
 	RemoveAll(path string) error
+
+	// WalkDir walks the tree rooted at root in lexical order, calling fn
+	// for each file and directory, with the semantics of filepath.WalkDir:
+	// paths passed to fn are root joined with the entry's relative path,
+	// and fn may return fs.SkipDir or fs.SkipAll.
+	WalkDir(root string, fn fs.WalkDirFunc) error
 }
@@ -9,12 +9,28 @@ type SkillValidator interface {
 	Validate(skill *domain.Skill) []ValidationError
 }
 
+// MetadataValidator validates plugin-metadata.json and the release manifest
+// against the categories skills are generated for.
+type MetadataValidator interface {
+	// Validate checks that every category has plugin metadata and a release
+	// version, and that every plugin maps onto a category.
+	Validate(metadata *domain.PluginMetadata, versions map[string]string, categories []string) []ValidationError
+}
+
+// NavValidator validates the docs site's nav against the docs tree.
+type NavValidator interface {
+	// Validate checks that every page the nav links to exists. exists
+	// reports whether a page, by its path relative to the docs root, does.
+	Validate(nav *domain.SiteNav, exists func(path string) bool) []ValidationError
+}
+
 // ValidationError represents a validation issue.
 type ValidationError struct {
 	Severity Severity // error or warning
 	Message  string
 	File     string
-	Line     int
+	Line     int // 1-based, in File; 0 if unknown
+	Column   int // 1-based, on Line; 0 if unknown
 }
 
 // Severity indicates how serious a validation issue is.
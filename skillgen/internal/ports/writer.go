@@ -32,3 +32,31 @@ type MarketplaceWriter interface {
 		outputPath string,
 	) error
 }
+
+// BuildCache persists what each category's last run parsed, built, and
+// wrote, keyed by category name.
+type BuildCache interface {
+	// Load returns the category's cache entry, or nil if there is none.
+	Load(category string) (*domain.CategoryCache, error)
+
+	// Store replaces the category's cache entry.
+	Store(category string, entry *domain.CategoryCache) error
+}
+
+// OutputCommitter swaps a run's staged output into place.
+type OutputCommitter interface {
+	// Commit writes every staged file whose content differs from what is
+	// on disk and deletes every file under ownedDirs that was not staged.
+	// Either all of it takes effect or, on failure, none of it does. It
+	// returns the number of files written or deleted.
+	Commit(files map[string][]byte, ownedDirs []string) (int, error)
+}
+
+// GeneratedManifestStore reads and writes the generated-files manifest.
+type GeneratedManifestStore interface {
+	// Read reads the manifest at path.
+	Read(path string) (*domain.GeneratedManifest, error)
+
+	// Write writes the manifest to path.
+	Write(manifest *domain.GeneratedManifest, path string) error
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// HashContent returns the hex sha256 of content.
+func HashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// CategoryInputs are everything a category's hub skill and its written
+// files are derived from. Two runs with equal inputs produce byte-identical
+// output, so the second can reuse the first's.
+type CategoryInputs struct {
+	Generator string              `json:"generator"`
+	Templates string              `json:"templates"`
+	DocsURL   string              `json:"docsURL"`
+	Category  domain.Category     `json:"category"`
+	Plugin    domain.PluginConfig `json:"plugin"`
+
+	// Nav is the docs site's nav entries under the category, which order
+	// and title its topics, if a nav was given.
+	Nav []domain.NavPage `json:"nav,omitempty"`
+
+	// Documents maps each discovered document's path to its content hash.
+	Documents map[string]string `json:"documents"`
+
+	// Snippets maps each snippet file the documents include to its content
+	// hash.
+	Snippets map[string]string `json:"snippets,omitempty"`
+
+	// Assets maps each local file the documents link to, and that can be
+	// read, to its content hash.
+	Assets map[string]string `json:"assets,omitempty"`
+
+	// Files lists every file under the docs root, which links are resolved
+	// against, so adding or removing one anywhere changes the key.
+	Files []string `json:"files,omitempty"`
+}
+
+// Key hashes the inputs into a single cache key.
+func (in CategoryInputs) Key() (string, error) {
+	// encoding/json writes map keys in sorted order, so equal inputs always
+	// serialize, and hash, identically.
+	content, err := json.Marshal(in)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash category inputs: %w", err)
+	}
+	return HashContent(content), nil
+}
+
+// HashTemplates hashes every template in dir, names included, so renaming,
+// adding, or editing any template changes the result.
+func HashTemplates(fs ports.FileSystem, dir string) (string, error) {
+	paths, err := fs.Glob(filepath.Join(dir, "*.tmpl"))
+	if err != nil {
+		return "", fmt.Errorf("failed to list templates in %s: %w", dir, err)
+	}
+	if len(paths) == 0 {
+		return "", fmt.Errorf("no templates found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, path := range paths {
+		content, err := fs.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.Base(path), len(content))
+		h.Write(content)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashTree returns the content hash of every file under dir, keyed by path.
+// A missing dir yields an empty map.
+func HashTree(fs ports.FileSystem, dir string) (map[string]string, error) {
+	files, err := listFiles(fs, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+
+	hashes := make(map[string]string, len(files))
+	for _, path := range files {
+		content, err := fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		hashes[path] = HashContent(content)
+	}
+
+	return hashes, nil
+}
+
+// TreeUnchanged reports whether the files under dir are exactly those in
+// want, with the same content: nothing edited, added, or removed since want
+// was recorded by HashTree.
+func TreeUnchanged(fs ports.FileSystem, dir string, want map[string]string) bool {
+	if len(want) == 0 {
+		return false
+	}
+
+	got, err := HashTree(fs, dir)
+	if err != nil || len(got) != len(want) {
+		return false
+	}
+	for path, hash := range want {
+		if got[path] != hash {
+			return false
+		}
+	}
+	return true
+}
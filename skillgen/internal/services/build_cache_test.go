@@ -0,0 +1,123 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+func testInputs() CategoryInputs {
+	return CategoryInputs{
+		Generator: "1.2.3",
+		Templates: "templates-hash",
+		DocsURL:   "https://adaptive-enforcement-lab.com",
+		Category:  domain.Category{Name: "patterns", SourceDir: "patterns"},
+		Plugin:    domain.PluginConfig{Description: "d"},
+		Documents: map[string]string{"/docs/patterns/index.md": "a", "/docs/patterns/x/index.md": "b"},
+	}
+}
+
+func TestCategoryInputs_Key(t *testing.T) {
+	base, err := testInputs().Key()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	again, _ := testInputs().Key()
+	if again != base {
+		t.Error("equal inputs produced different keys")
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*CategoryInputs)
+	}{
+		{"generator", func(in *CategoryInputs) { in.Generator = "1.2.4" }},
+		{"templates", func(in *CategoryInputs) { in.Templates = "other" }},
+		{"docs URL", func(in *CategoryInputs) { in.DocsURL = "https://staging.example.com" }},
+		{"source dir", func(in *CategoryInputs) { in.Category.SourceDir = "guides/patterns" }},
+		{"plugin metadata", func(in *CategoryInputs) { in.Plugin.Description = "changed" }},
+		{"document content", func(in *CategoryInputs) { in.Documents["/docs/patterns/x/index.md"] = "c" }},
+		{"document added", func(in *CategoryInputs) { in.Documents["/docs/patterns/y/index.md"] = "d" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := testInputs()
+			tt.mutate(&in)
+			key, err := in.Key()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key == base {
+				t.Errorf("changing the %s did not change the key", tt.name)
+			}
+		})
+	}
+}
+
+func TestHashTemplates(t *testing.T) {
+	fs := filesystem.NewMemoryFileSystem()
+	fs.WriteFile("templates/skill.tmpl", []byte("skill"), 0644)
+	fs.WriteFile("templates/reference.tmpl", []byte("reference"), 0644)
+	fs.WriteFile("templates/notes.txt", []byte("ignored"), 0644)
+
+	before, err := HashTemplates(fs, "templates")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	fs.WriteFile("templates/notes.txt", []byte("still ignored"), 0644)
+	if after, _ := HashTemplates(fs, "templates"); after != before {
+		t.Error("a non-template file changed the hash")
+	}
+
+	fs.WriteFile("templates/skill.tmpl", []byte("skill v2"), 0644)
+	if after, _ := HashTemplates(fs, "templates"); after == before {
+		t.Error("editing a template did not change the hash")
+	}
+
+	if _, err := HashTemplates(fs, "missing"); err == nil {
+		t.Error("expected an error for a directory without templates")
+	}
+}
+
+func TestTreeUnchanged(t *testing.T) {
+	fs := filesystem.NewMemoryFileSystem()
+	fs.WriteFile("out/skills/patterns/SKILL.md", []byte("skill"), 0644)
+	fs.WriteFile("out/skills/patterns/library/index.md", []byte("library"), 0644)
+
+	recorded, err := HashTree(fs, "out/skills")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(recorded) != 2 {
+		t.Fatalf("expected 2 hashed files, got %d", len(recorded))
+	}
+
+	if !TreeUnchanged(fs, "out/skills", recorded) {
+		t.Error("expected an untouched tree to be unchanged")
+	}
+
+	fs.WriteFile("out/skills/patterns/SKILL.md", []byte("hand edit"), 0644)
+	if TreeUnchanged(fs, "out/skills", recorded) {
+		t.Error("expected an edited file to be detected")
+	}
+	fs.WriteFile("out/skills/patterns/SKILL.md", []byte("skill"), 0644)
+
+	fs.WriteFile("out/skills/patterns/extra.md", []byte("extra"), 0644)
+	if TreeUnchanged(fs, "out/skills", recorded) {
+		t.Error("expected an added file to be detected")
+	}
+	fs.RemoveAll("out/skills/patterns/extra.md")
+
+	fs.RemoveAll("out/skills/patterns/library")
+	if TreeUnchanged(fs, "out/skills", recorded) {
+		t.Error("expected a removed file to be detected")
+	}
+
+	if TreeUnchanged(fs, "out/skills", nil) {
+		t.Error("expected nothing recorded to never count as unchanged")
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// DriftChecker compares the output of a generation run, captured in memory,
+// against what is committed on disk. It is the CI gate for hand edits and
+// stale regenerations: if regenerating would change anything, the committed
+// output has drifted from its sources.
+type DriftChecker struct {
+	disk ports.FileSystem
+}
+
+// NewDriftChecker creates a drift checker that reads committed files from disk.
+func NewDriftChecker(disk ports.FileSystem) *DriftChecker {
+	return &DriftChecker{disk: disk}
+}
+
+// Check compares every generated file byte-for-byte with its committed copy.
+// ownedDirs are directories generation rewrites wholesale (each hub's
+// skills/ directory); any committed file under them that generation no
+// longer produces is reported too, since a regeneration would delete it.
+// Results are sorted by path.
+func (c *DriftChecker) Check(generated map[string][]byte, ownedDirs []string) ([]domain.FileDrift, error) {
+	var drifts []domain.FileDrift
+
+	for path, content := range generated {
+		if !c.disk.Exists(path) {
+			drifts = append(drifts, domain.FileDrift{
+				Path: path,
+				Kind: domain.DriftMissing,
+				Diff: unifiedDiff("/dev/null", "b/"+path, "", string(content)),
+			})
+			continue
+		}
+
+		committed, err := c.disk.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read committed file %s: %w", path, err)
+		}
+		if diff := unifiedDiff("a/"+path, "b/"+path, string(committed), string(content)); diff != "" {
+			drifts = append(drifts, domain.FileDrift{Path: path, Kind: domain.DriftModified, Diff: diff})
+		}
+	}
+
+	for _, dir := range ownedDirs {
+		committedFiles, err := listFiles(c.disk, dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list committed files in %s: %w", dir, err)
+		}
+
+		for _, path := range committedFiles {
+			if _, ok := generated[path]; ok {
+				continue
+			}
+			committed, err := c.disk.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read committed file %s: %w", path, err)
+			}
+			drifts = append(drifts, domain.FileDrift{
+				Path: path,
+				Kind: domain.DriftUnexpected,
+				Diff: unifiedDiff("a/"+path, "/dev/null", string(committed), ""),
+			})
+		}
+	}
+
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Path < drifts[j].Path })
+	return drifts, nil
+}
+
+// listFiles returns every regular file under dir, walking it through the
+// port so it works against any ports.FileSystem. A missing dir yields no
+// files.
+func listFiles(disk ports.FileSystem, dir string) ([]string, error) {
+	if !disk.IsDir(dir) {
+		return nil, nil
+	}
+
+	var files []string
+	err := disk.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		files = append(files, filepath.Clean(path))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
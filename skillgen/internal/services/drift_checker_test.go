@@ -0,0 +1,122 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+func TestDriftChecker_Check(t *testing.T) {
+	disk := filesystem.NewMemoryFileSystem()
+	disk.WriteFile("README.md", []byte("# Skills\n"), 0644)
+	disk.WriteFile("plugins/patterns/skills/patterns/SKILL.md", []byte("one\ntwo\nthree\n"), 0644)
+	disk.WriteFile("plugins/patterns/skills/patterns/library/stale.md", []byte("gone\n"), 0644)
+	disk.WriteFile("plugins/patterns/CHANGELOG.md", []byte("not owned\n"), 0644)
+
+	generated := map[string][]byte{
+		"README.md": []byte("# Skills\n"),
+		"plugins/patterns/skills/patterns/SKILL.md":         []byte("one\n2\nthree\n"),
+		"plugins/patterns/skills/patterns/library/index.md": []byte("new\n"),
+	}
+
+	drifts, err := NewDriftChecker(disk).Check(generated, []string{"plugins/patterns/skills"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]domain.DriftKind{
+		"plugins/patterns/skills/patterns/SKILL.md":         domain.DriftModified,
+		"plugins/patterns/skills/patterns/library/index.md": domain.DriftMissing,
+		"plugins/patterns/skills/patterns/library/stale.md": domain.DriftUnexpected,
+	}
+	if len(drifts) != len(want) {
+		t.Fatalf("expected %d drifts, got %d: %+v", len(want), len(drifts), drifts)
+	}
+	for i, d := range drifts {
+		if want[d.Path] != d.Kind {
+			t.Errorf("%s: kind = %q, want %q", d.Path, d.Kind, want[d.Path])
+		}
+		if i > 0 && drifts[i-1].Path > d.Path {
+			t.Errorf("drifts not sorted by path: %q before %q", drifts[i-1].Path, d.Path)
+		}
+		if d.Diff == "" {
+			t.Errorf("%s: expected a diff", d.Path)
+		}
+	}
+}
+
+func TestDriftChecker_NoDrift(t *testing.T) {
+	disk := filesystem.NewMemoryFileSystem()
+	disk.WriteFile("plugins/build/skills/build/SKILL.md", []byte("same\n"), 0644)
+
+	generated := map[string][]byte{"plugins/build/skills/build/SKILL.md": []byte("same\n")}
+
+	drifts, err := NewDriftChecker(disk).Check(generated, []string{"plugins/build/skills"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("expected no drift, got %+v", drifts)
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "identical",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "single line change with context",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			new:  "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			want: "--- a/f\n+++ b/f\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "distant changes make separate hunks",
+			old:  "a\n1\n2\n3\n4\n5\n6\n7\nb\n",
+			new:  "A\n1\n2\n3\n4\n5\n6\n7\nB\n",
+			want: "--- a/f\n+++ b/f\n@@ -1,4 +1,4 @@\n-a\n+A\n 1\n 2\n 3\n@@ -6,4 +6,4 @@\n 5\n 6\n 7\n-b\n+B\n",
+		},
+		{
+			name: "new file",
+			old:  "",
+			new:  "x\n",
+			want: "--- a/f\n+++ b/f\n@@ -0,0 +1,1 @@\n+x\n",
+		},
+		{
+			name: "missing final newline",
+			old:  "x\n",
+			new:  "x",
+			want: "--- a/f\n+++ b/f\n@@ -1,1 +1,1 @@\n-x\n+x\n\\ No newline at end of file\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := unifiedDiff("a/f", "b/f", tt.old, tt.new)
+			if got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffFindsMinimalEdit(t *testing.T) {
+	old := "a\nb\nc\nd\ne\nf\n"
+	new := "a\nc\nd\nX\ne\nf\n"
+
+	got := unifiedDiff("a/f", "b/f", old, new)
+	if strings.Count(got, "\n-") != 1 || strings.Count(got, "\n+") != 2 {
+		t.Errorf("expected one deletion and one insertion (plus header), got:\n%s", got)
+	}
+}
@@ -0,0 +1,74 @@
+package extractor
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+// bundleAssets reads the local files docs link to, and bundles those rules
+// allow under library/, recording in links where each went so links to it
+// are rewritten to the copy. It returns the bundled assets, and by doc path
+// the assets each doc links to that were not bundled. A file that does not
+// exist is left to the broken link check.
+func (b *HubBuilder) bundleAssets(docs []*domain.Document, category domain.Category, rules domain.AssetRules, links *hubLinks) ([]domain.LibraryAsset, map[string][]domain.SkippedAsset) {
+	if b.fs == nil {
+		return nil, nil
+	}
+
+	var assets []domain.LibraryAsset
+	skipped := make(map[string][]domain.SkippedAsset)
+	reasons := make(map[string]string)
+	for _, doc := range docs {
+		for _, ref := range doc.Assets {
+			file := ref.Path
+			rel, err := filepath.Rel(filepath.Dir(doc.Path), file)
+			if err != nil {
+				continue
+			}
+			docsRel := path.Join(path.Dir(links.docsRel(doc.Path)), filepath.ToSlash(rel))
+			if docsRel == ".." || strings.HasPrefix(docsRel, "../") {
+				continue
+			}
+			if _, bundled := links.assets[docsRel]; bundled {
+				continue
+			}
+
+			reason, seen := reasons[docsRel]
+			if !seen {
+				if !b.fs.Exists(file) || b.fs.IsDir(file) {
+					continue
+				}
+				content, err := b.fs.ReadFile(file)
+				if err != nil {
+					reason = fmt.Sprintf("cannot be read: %v", err)
+				} else {
+					reason = rules.Check(docsRel, len(content))
+				}
+				if reason == "" {
+					relPath := libraryAssetPath(docsRel, category)
+					assets = append(assets, domain.LibraryAsset{RelPath: relPath, Content: content, SourcePath: file})
+					links.assets[docsRel] = relPath
+					continue
+				}
+				reasons[docsRel] = reason
+			}
+			skipped[doc.Path] = append(skipped[doc.Path], domain.SkippedAsset{Path: docsRel, Reason: reason, Position: ref.Position})
+		}
+	}
+	return assets, skipped
+}
+
+// libraryAssetPath returns where the asset at rel, relative to the docs
+// root, goes under the hub's library/: at its own path under the category,
+// mirroring the docs tree as library files do, or under "_assets/" at its
+// path under the docs root if it lives outside the category.
+func libraryAssetPath(rel string, category domain.Category) string {
+	if inCategory, ok := strings.CutPrefix(rel, category.SourceDir+"/"); ok {
+		return inCategory
+	}
+	return "_assets/" + rel
+}
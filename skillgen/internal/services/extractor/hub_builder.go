@@ -2,8 +2,10 @@ package extractor
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
+	"unicode"
 
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
@@ -18,11 +20,50 @@ import (
 type HubBuilder struct {
 	topicExtractor      ports.TopicExtractor
 	admonitionConverter ports.AdmonitionConverter
+	tabConverter        ports.TabConverter
+	markupNormalizer    ports.MarkupNormalizer
+	linkRewriter        ports.LinkRewriter
+	baseURL             string
+	docsRoot            string
+	sourceCommit        string
+	nav                 *domain.SiteNav
+	tree                *domain.DocsTree
+	fs                  ports.FileSystem
 }
 
-// NewHubBuilder creates a new hub builder.
-func NewHubBuilder(topicExtractor ports.TopicExtractor, admonitionConverter ports.AdmonitionConverter) *HubBuilder {
-	return &HubBuilder{topicExtractor: topicExtractor, admonitionConverter: admonitionConverter}
+// NewHubBuilder creates a new hub builder whose source links are built on
+// baseURL, the docs site root, for documents under docsRoot. sourceCommit,
+// if set, is the docs commit the documents were read at, recorded in each
+// library file's source note. nav, if set, is the docs site's navigation:
+// groups and topics follow its order instead of A–Z, and its titles
+// override the documents' own. tree, if set, lists the files under the docs
+// root, which links that leave the hub are checked against. fs, if set, is
+// the docs source, which the local files docs link to are read from to
+// bundle them with the hub.
+func NewHubBuilder(
+	topicExtractor ports.TopicExtractor,
+	admonitionConverter ports.AdmonitionConverter,
+	tabConverter ports.TabConverter,
+	markupNormalizer ports.MarkupNormalizer,
+	linkRewriter ports.LinkRewriter,
+	baseURL, docsRoot, sourceCommit string,
+	nav *domain.SiteNav,
+	tree *domain.DocsTree,
+	fs ports.FileSystem,
+) *HubBuilder {
+	return &HubBuilder{
+		topicExtractor:      topicExtractor,
+		admonitionConverter: admonitionConverter,
+		tabConverter:        tabConverter,
+		markupNormalizer:    markupNormalizer,
+		linkRewriter:        linkRewriter,
+		baseURL:             baseURL,
+		docsRoot:            docsRoot,
+		sourceCommit:        sourceCommit,
+		nav:                 nav,
+		tree:                tree,
+		fs:                  fs,
+	}
 }
 
 // referenceShift levels: a topic's body is wrapped under "### Title" in
@@ -32,20 +73,56 @@ const (
 	rootReferenceShift  = 1
 )
 
+// rootPagesGroup is the key of the group that holds the pages directly
+// under the category root other than its index.md, titled as the category
+// is. Only an index.md heads a group of its own.
+const rootPagesGroup = ""
+
+// sameURLPages returns, for each page the site serves at the same URL as
+// an index.md, such as foo.md beside foo/index.md, the docs-relative path
+// of that index.md. MkDocs builds only one of them.
+func (b *HubBuilder) sameURLPages(docs []*domain.Document, category domain.Category) map[string]string {
+	indexes := make(map[string]string)
+	for _, doc := range docs {
+		if rel := b.docsRel(doc); pageName(rel) == "" {
+			indexes[strings.Join(categorySegments(rel, category), "/")] = rel
+		}
+	}
+	same := make(map[string]string)
+	for _, doc := range docs {
+		rel := b.docsRel(doc)
+		if pageName(rel) == "" {
+			continue
+		}
+		if index, ok := indexes[strings.Join(categorySegments(rel, category), "/")]; ok {
+			same[doc.Path] = index
+		}
+	}
+	return same
+}
+
 // Build assembles the hub skill for a category from its documents.
 func (b *HubBuilder) Build(category string, docs []*domain.Document, pluginCfg domain.PluginConfig) (*domain.Skill, error) {
+	source := domain.NewCategory(category, pluginCfg)
+
 	var rootDoc *domain.Document
 	groupRoots := make(map[string]*domain.Document)
 	var rest []*domain.Document
 	libraryFiles := make([]domain.LibraryFile, 0, len(docs))
+	links := newHubLinks(b.baseURL, b.docsRoot, source, b.tree, docs)
+	assets, skipped := b.bundleAssets(docs, source, pluginCfg.Assets, links)
+	sameURL := b.sameURLPages(docs, source)
 
 	for _, doc := range docs {
-		segments := categorySegments(doc.Path, category)
-		libraryFiles = append(libraryFiles, b.libraryFile(doc, segments, category))
-		switch len(segments) {
-		case 0:
+		segments := categorySegments(b.docsRel(doc), source)
+		lf := b.libraryFile(doc, source, pluginCfg, links)
+		lf.Skipped = skipped[doc.Path]
+		lf.SameURL = sameURL[doc.Path]
+		libraryFiles = append(libraryFiles, lf)
+		switch {
+		case len(segments) == 0:
 			rootDoc = doc
-		case 1:
+		case len(segments) == 1 && pageName(b.docsRel(doc)) == "" && b.place(doc, source).group == "":
 			groupRoots[segments[0]] = doc
 			rest = append(rest, doc)
 		default:
@@ -58,25 +135,37 @@ func (b *HubBuilder) Build(category string, docs []*domain.Document, pluginCfg d
 	}
 
 	groups := make(map[string]*domain.TopicGroup)
+	groupPlaces := make(map[*domain.TopicGroup]placement)
+	topicPlaces := make(map[string]placement)
 	for _, doc := range rest {
-		segments := categorySegments(doc.Path, category)
+		segments := categorySegments(b.docsRel(doc), source)
+		place := b.place(doc, source)
 		groupKey := segments[0]
+		if len(segments) == 1 && pageName(b.docsRel(doc)) != "" {
+			groupKey = rootPagesGroup
+		}
+		if place.group != "" {
+			groupKey = place.group
+		}
 
 		group, ok := groups[groupKey]
 		if !ok {
 			group = &domain.TopicGroup{Title: humanize(groupKey)}
 			groups[groupKey] = group
+			groupPlaces[group] = place
 		}
 
 		if groupRootDoc, isRoot := groupRoots[groupKey]; isRoot && doc.Path == groupRootDoc.Path {
-			group.Title = doc.Frontmatter.Title
-			group.Description = firstSentence(doc.Frontmatter.Description)
-			group.URL = buildSourceURL(doc.Path, category)
+			group.Title = nonEmpty(place.title, doc.Frontmatter.Title)
+			groupPlaces[group] = place
+			group.Description = firstSentence(nonEmpty(place.description, doc.Frontmatter.Description))
+			group.URL = buildSourceURL(b.baseURL, b.docsRel(doc), source)
+			group.SourcePath = doc.Path
 			// A group's own body sits alongside its child topics under the
 			// same "## Group" heading, so it must shift by the same amount
 			// as a topic body — otherwise its internal headings can collide
 			// with a sibling topic's "### Title" wrapper level.
-			group.ReferenceBody = prepareReferenceBody(b.admonitionConverter.Convert(doc.RawContent), topicReferenceShift)
+			group.ReferenceBody = b.referenceBody(doc.RawContent, pluginCfg, topicReferenceShift)
 			continue
 		}
 
@@ -84,38 +173,138 @@ func (b *HubBuilder) Build(category string, docs []*domain.Document, pluginCfg d
 		if err != nil {
 			return nil, err
 		}
-		topic.ReferenceBody = prepareReferenceBody(b.admonitionConverter.Convert(doc.RawContent), topicReferenceShift)
+		topic.Title = nonEmpty(place.title, topic.Title)
+		if place.description != "" {
+			topic.Description = firstSentence(place.description)
+		}
+		topic.ReferenceBody = b.referenceBody(doc.RawContent, pluginCfg, topicReferenceShift)
 		group.Topics = append(group.Topics, *topic)
+		topicPlaces[doc.Path] = place
+		// A group without a doc of its own sits where its first topic does.
+		if _, hasRoot := groupRoots[groupKey]; !hasRoot && place.before(groupPlaces[group]) {
+			groupPlaces[group] = place
+		}
 	}
 
-	sortedGroups := make([]domain.TopicGroup, 0, len(groups))
+	if group, ok := groups[rootPagesGroup]; ok {
+		group.Title = nonEmpty(b.place(rootDoc, source).title, rootDoc.Frontmatter.Title)
+	}
+
+	// Without weights or a nav every place is equal, and the order is A–Z
+	// by title.
+	type placedGroup struct {
+		group domain.TopicGroup
+		place placement
+	}
+	placed := make([]placedGroup, 0, len(groups))
 	for _, group := range groups {
 		sort.Slice(group.Topics, func(i, j int) bool {
+			pi, pj := topicPlaces[group.Topics[i].SourcePath], topicPlaces[group.Topics[j].SourcePath]
+			if pi.before(pj) || pj.before(pi) {
+				return pi.before(pj)
+			}
 			return group.Topics[i].Title < group.Topics[j].Title
 		})
-		sortedGroups = append(sortedGroups, *group)
+		placed = append(placed, placedGroup{group: *group, place: groupPlaces[group]})
 	}
-	sort.Slice(sortedGroups, func(i, j int) bool {
-		return sortedGroups[i].Title < sortedGroups[j].Title
+	sort.Slice(placed, func(i, j int) bool {
+		pi, pj := placed[i].place, placed[j].place
+		if pi.before(pj) || pj.before(pi) {
+			return pi.before(pj)
+		}
+		return placed[i].group.Title < placed[j].group.Title
 	})
+	sortedGroups := make([]domain.TopicGroup, len(placed))
+	for i, p := range placed {
+		sortedGroups[i] = p.group
+	}
 
+	// SKILL.md links into library/ like its topics do, not to the docs
+	// tree the overview was written in.
+	overview := b.linkRewriter.Rewrite(firstSentences(rootDoc.Introduction, 3), func(dest string) string {
+		return links.skillDest(rootDoc, dest)
+	})
 	metadata := domain.SkillMetadata{
 		Name:          category,
-		Title:         rootDoc.Frontmatter.Title,
+		Title:         nonEmpty(b.place(rootDoc, source).title, rootDoc.Frontmatter.Title),
 		Description:   pluginCfg.Description,
 		Category:      category,
 		Tags:          pluginCfg.Tags,
-		Overview:      firstSentences(rootDoc.Introduction, 3),
-		ReferenceBody: prepareReferenceBody(b.admonitionConverter.Convert(rootDoc.RawContent), rootReferenceShift),
+		Overview:      overview,
+		ReferenceBody: b.referenceBody(rootDoc.RawContent, pluginCfg, rootReferenceShift),
 		SourcePath:    rootDoc.Path,
-		SourceURL:     buildSourceURL(rootDoc.Path, category),
+		SourceURL:     buildSourceURL(b.baseURL, b.docsRel(rootDoc), source),
 	}
 
-	return &domain.Skill{
+	hub := &domain.Skill{
 		Metadata:     metadata,
 		Groups:       sortedGroups,
 		LibraryFiles: libraryFiles,
-	}, nil
+		Assets:       assets,
+	}
+
+	// reference.md's anchors depend on every heading before them, so its
+	// links can only be rewritten once the whole hub is laid out.
+	links.anchorHub(hub)
+	links.rewriteReference(hub, func(doc *domain.Document, body string) string {
+		return b.linkRewriter.Rewrite(body, func(dest string) string {
+			return links.referenceDest(doc, dest)
+		})
+	})
+
+	return hub, nil
+}
+
+// placement is where a doc goes in its hub, decided by the doc's own
+// skillgen frontmatter and then the nav: the title and description that
+// replace its own, the group it moves to, and its order among its siblings.
+type placement struct {
+	title       string
+	description string
+	group       string
+	weight      int
+	navRank     int
+}
+
+// before reports whether p sorts ahead of q: lower weight first, then
+// earlier in the nav.
+func (p placement) before(q placement) bool {
+	if p.weight != q.weight {
+		return p.weight < q.weight
+	}
+	return p.navRank < q.navRank
+}
+
+// place works out doc's placement. A doc the nav does not list, or every
+// doc when there is no nav, ranks after every listed one. Malformed
+// directives are reported when the doc is read, and ignored here.
+func (b *HubBuilder) place(doc *domain.Document, category domain.Category) placement {
+	directives, _ := doc.Frontmatter.Directives()
+	p := placement{
+		title:       directives.Title,
+		description: directives.Description,
+		group:       directives.Group,
+		weight:      directives.Weight,
+		navRank:     math.MaxInt,
+	}
+	if page, position, ok := b.nav.Lookup(b.docsRel(doc)); ok {
+		p.title = nonEmpty(p.title, page.Title)
+		p.navRank = position
+	}
+	return p
+}
+
+// docsRel returns doc's slash-separated path relative to the docs root.
+func (b *HubBuilder) docsRel(doc *domain.Document) string {
+	return docsRelPath(b.docsRoot, doc.Path)
+}
+
+// nonEmpty returns value, or fallback if value is empty.
+func nonEmpty(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
 }
 
 // libraryFile builds the verbatim library/ entry for a single doc: its
@@ -124,30 +313,62 @@ func (b *HubBuilder) Build(category string, docs []*domain.Document, pluginCfg d
 // standalone file), with a source URL note added right after the title.
 // RelPath mirrors the doc's own path under the category, so the library/
 // tree is a 1:1 copy of the source doc tree (the category root doc becomes
-// "index.md", a nested doc keeps its full relative path).
-func (b *HubBuilder) libraryFile(doc *domain.Document, segments []string, category string) domain.LibraryFile {
-	relPath := "index.md"
-	if len(segments) > 0 {
-		relPath = strings.Join(segments, "/") + "/index.md"
+// "index.md", a nested doc or page keeps its full relative path).
+func (b *HubBuilder) libraryFile(doc *domain.Document, category domain.Category, pluginCfg domain.PluginConfig, links *hubLinks) domain.LibraryFile {
+	relPath := libraryRelPath(b.docsRel(doc), category)
+
+	note := "Source: " + buildSourceURL(b.baseURL, b.docsRel(doc), category)
+	if b.sourceCommit != "" {
+		note += " (docs commit " + b.sourceCommit + ")"
 	}
 
-	body := b.admonitionConverter.Convert(doc.RawContent)
-	content := insertSourceNoteAfterTitle(body, buildSourceURL(doc.Path, category))
+	body, flagged := b.toMarkdown(doc.RawContent, pluginCfg)
+	for i := range flagged {
+		flagged[i].Position = doc.Lines.Locate(flagged[i].Position)
+	}
+	var broken []domain.Link
+	placed := make([]bool, len(doc.Links))
+	body = b.linkRewriter.Rewrite(body, func(dest string) string {
+		rewritten, ok := links.libraryDest(doc, dest)
+		if !ok {
+			broken = append(broken, domain.Link{Dest: dest, Position: linkPosition(doc.Links, placed, dest)})
+		}
+		return rewritten
+	})
+	content := insertSourceNoteAfterTitle(body, note)
+
+	return domain.LibraryFile{RelPath: relPath, Content: content, SourcePath: doc.Path, Flagged: flagged, BrokenLinks: broken}
+}
+
+// toMarkdown converts a doc's MkDocs-only syntax to standard markdown:
+// markup such as attr_list and emoji first, while code blocks and their
+// annotations are still where the docs put them, then content tabs, so
+// admonitions inside a tab are found at their de-indented position, then
+// admonitions in the plugin's style. It also returns the markup the
+// plugin's rules flag.
+func (b *HubBuilder) toMarkdown(content string, pluginCfg domain.PluginConfig) (string, []domain.FlaggedMarkup) {
+	content, flagged := b.markupNormalizer.Normalize(content, pluginCfg.Markup)
+	return b.admonitionConverter.Convert(b.tabConverter.Convert(content), pluginCfg.AdmonitionStyle), flagged
+}
 
-	return domain.LibraryFile{RelPath: relPath, Content: content}
+// referenceBody converts a doc's body for reference.md, with its headings
+// shifted by shift levels. Flagged markup is reported from the doc's
+// library file instead, so each is reported once.
+func (b *HubBuilder) referenceBody(content string, pluginCfg domain.PluginConfig, shift int) string {
+	body, _ := b.toMarkdown(content, pluginCfg)
+	return prepareReferenceBody(body, shift)
 }
 
-// insertSourceNoteAfterTitle inserts a "Source: <url>" line right after a
+// insertSourceNoteAfterTitle inserts a "Source: <url>" note right after a
 // doc's leading "# Title" heading (its first line, since doc bodies always
 // start with the title). If the body doesn't start with a heading (should
 // not happen for AEL docs, but handled defensively), the note is simply
 // prepended.
-func insertSourceNoteAfterTitle(body, url string) string {
+func insertSourceNoteAfterTitle(body, note string) string {
 	// Source docs commonly have a blank line between frontmatter and the
 	// leading "# Title", so the title isn't always literally line zero.
 	trimmed := strings.TrimLeft(body, "\n")
 	lines := strings.SplitN(trimmed, "\n", 2)
-	note := "Source: " + url
 
 	if len(lines) > 0 && isHeading(strings.TrimLeft(lines[0], " ")) {
 		rest := ""
@@ -177,7 +398,9 @@ func humanize(slug string) string {
 // firstSentences returns the first n sentences of the first paragraph of
 // text, trimmed and joined back into a single line. Only the first
 // paragraph is considered: admonitions and other blocks that follow a blank
-// line in a doc's introduction aren't prose meant for a short overview.
+// line in a doc's introduction aren't prose meant for a short overview. A
+// sentence ends at punctuation followed by a space, so a link such as
+// "[spoke](spoke/index.md)" is never cut short.
 func firstSentences(text string, n int) string {
 	trimmed := strings.TrimSpace(text)
 	if trimmed == "" {
@@ -192,7 +415,7 @@ func firstSentences(text string, n int) string {
 	count := 0
 	start := 0
 	for i, r := range trimmed {
-		if r == '.' || r == '!' || r == '?' {
+		if (r == '.' || r == '!' || r == '?') && (i+1 == len(trimmed) || unicode.IsSpace(rune(trimmed[i+1]))) {
 			b.WriteString(trimmed[start : i+1])
 			count++
 			start = i + 1
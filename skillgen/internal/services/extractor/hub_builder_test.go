@@ -1,16 +1,27 @@
 package extractor
 
 import (
+	"fmt"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/parser"
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
 )
 
+// testCategories mirrors the repo's own plugin-metadata.json.
+var testCategories = domain.CategorySet{
+	{Name: "build", SourceDir: "build"},
+	{Name: "enforce", SourceDir: "enforce"},
+	{Name: "patterns", SourceDir: "patterns"},
+	{Name: "secure", SourceDir: "secure"},
+}
+
 func newTestHubBuilder() *HubBuilder {
-	return NewHubBuilder(NewTopicExtractor(), parser.NewAdmonitionConverter())
+	return NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", testCategories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "", nil, nil, nil)
 }
 
 func doc(pathSegments []string, title, description, introduction string) *domain.Document {
@@ -264,3 +275,693 @@ func TestHubBuilderLibraryFilesHandleLeadingBlankLine(t *testing.T) {
 		t.Errorf("root library content = %q, want %q (title must come before the source note)", root.Content, want)
 	}
 }
+
+func TestHubBuilderRecordsSourceCommitInLibraryFiles(t *testing.T) {
+	builder := NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", testCategories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "0123abc", nil, nil, nil)
+	docs := []*domain.Document{
+		docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "", "# Patterns\n\nReusable design patterns."),
+	}
+
+	hub, err := builder.Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "# Patterns\n\nSource: https://adaptive-enforcement-lab.com/patterns/ (docs commit 0123abc)\n\nReusable design patterns."
+	if len(hub.LibraryFiles) != 1 || hub.LibraryFiles[0].Content != want {
+		t.Errorf("library files = %+v, want one with content %q", hub.LibraryFiles, want)
+	}
+}
+
+func TestHubBuilderBuildsFromPluginSourceDir(t *testing.T) {
+	pluginCfg := domain.PluginConfig{Description: "d", SourceDir: "guides/operations"}
+	categories := domain.CategorySet{domain.NewCategory("ops", pluginCfg)}
+	builder := NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", categories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "", nil, nil, nil)
+
+	docs := []*domain.Document{
+		docWithBody([]string{"docs", "guides", "operations", "index.md"}, "Operations", "d", "Intro.", "# Operations\n"),
+		docWithBody([]string{"docs", "guides", "operations", "runbooks", "index.md"}, "Runbooks", "Runbooks.", "", "# Runbooks\n"),
+		docWithBody([]string{"docs", "guides", "operations", "runbooks", "paging", "index.md"}, "Paging", "On-call paging.", "", "# Paging\n"),
+	}
+
+	hub, err := builder.Build("ops", docs, pluginCfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hub.Metadata.Name != "ops" {
+		t.Errorf("Name = %q, want the plugin key %q", hub.Metadata.Name, "ops")
+	}
+	if want := "https://adaptive-enforcement-lab.com/guides/operations/"; hub.Metadata.SourceURL != want {
+		t.Errorf("SourceURL = %q, want %q", hub.Metadata.SourceURL, want)
+	}
+	if len(hub.Groups) != 1 || len(hub.Groups[0].Topics) != 1 {
+		t.Fatalf("expected one group with one topic, got %+v", hub.Groups)
+	}
+
+	topic := hub.Groups[0].Topics[0]
+	if want := "https://adaptive-enforcement-lab.com/guides/operations/runbooks/paging/"; topic.URL != want {
+		t.Errorf("topic URL = %q, want %q", topic.URL, want)
+	}
+	if want := "library/runbooks/paging/index.md"; topic.LibraryPath != want {
+		t.Errorf("topic LibraryPath = %q, want %q", topic.LibraryPath, want)
+	}
+}
+
+func TestHubBuilderIncludesSiblingPages(t *testing.T) {
+	builder := NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", testCategories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "", nil, nil, nil)
+	pluginCfg := domain.PluginConfig{Description: "d"}
+
+	docs := []*domain.Document{
+		docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "", "# Patterns\n"),
+		docWithBody([]string{"docs", "patterns", "idempotency", "index.md"}, "Idempotency", "Safe reruns.", "", "# Idempotency\n"),
+		docWithBody([]string{"docs", "patterns", "idempotency", "checkpoints.md"}, "Checkpoints", "Resume from progress.", "", "# Checkpoints\n"),
+		docWithBody([]string{"docs", "patterns", "retries.md"}, "Retries", "Back off and retry.", "", "# Retries\n"),
+	}
+
+	hub, err := builder.Build("patterns", docs, pluginCfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byTitle := make(map[string]domain.TopicGroup)
+	for _, group := range hub.Groups {
+		byTitle[group.Title] = group
+	}
+
+	idempotency, ok := byTitle["Idempotency"]
+	if !ok || len(idempotency.Topics) != 1 {
+		t.Fatalf("expected the checkpoints page as the idempotency group's topic, got %+v", hub.Groups)
+	}
+	topic := idempotency.Topics[0]
+	if want := "https://adaptive-enforcement-lab.com/patterns/idempotency/checkpoints/"; topic.URL != want {
+		t.Errorf("topic URL = %q, want %q", topic.URL, want)
+	}
+	if want := "library/idempotency/checkpoints.md"; topic.LibraryPath != want {
+		t.Errorf("topic LibraryPath = %q, want %q", topic.LibraryPath, want)
+	}
+
+	// A page directly under the category root is a topic of a group
+	// titled as the category; only an index.md heads a group of its own.
+	root, ok := byTitle["Patterns"]
+	if !ok || len(root.Topics) != 1 || root.SourcePath != "" {
+		t.Fatalf("expected the retries page as the Patterns group's only topic, got %+v", hub.Groups)
+	}
+	if want := "https://adaptive-enforcement-lab.com/patterns/retries/"; root.Topics[0].URL != want {
+		t.Errorf("topic URL = %q, want %q", root.Topics[0].URL, want)
+	}
+	if _, ok := byTitle["Retries"]; ok {
+		t.Errorf("expected no Retries group, got %+v", hub.Groups)
+	}
+
+	byPath := make(map[string]domain.LibraryFile)
+	for _, lf := range hub.LibraryFiles {
+		byPath[lf.RelPath] = lf
+	}
+	for _, path := range []string{"index.md", "idempotency/index.md", "idempotency/checkpoints.md", "retries.md"} {
+		if _, ok := byPath[path]; !ok {
+			t.Errorf("expected a library file at %s, got paths: %+v", path, keysOf(byPath))
+		}
+	}
+	if want := "Source: https://adaptive-enforcement-lab.com/patterns/idempotency/checkpoints/"; !strings.Contains(byPath["idempotency/checkpoints.md"].Content, want) {
+		t.Errorf("expected the page's library file to carry %q, got %q", want, byPath["idempotency/checkpoints.md"].Content)
+	}
+}
+
+func TestHubBuilderReportsPagesAtAnIndexURL(t *testing.T) {
+	docs := []*domain.Document{
+		docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "", "# Patterns\n"),
+		docWithBody([]string{"docs", "patterns", "foo.md"}, "Foo Page", "d", "", "# Foo Page\n"),
+		docWithBody([]string{"docs", "patterns", "foo", "index.md"}, "Foo", "d", "", "# Foo\n"),
+		docWithBody([]string{"docs", "patterns", "foo", "bar.md"}, "Bar", "d", "", "# Bar\n"),
+	}
+
+	hub, err := newTestHubBuilder().Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var groups []string
+	for _, group := range hub.Groups {
+		groups = append(groups, fmt.Sprintf("%s:%d", group.Title, len(group.Topics)))
+	}
+	if want := []string{"Foo:1", "Patterns:1"}; !reflect.DeepEqual(groups, want) {
+		t.Errorf("groups = %q, want %q", groups, want)
+	}
+
+	for _, lf := range hub.LibraryFiles {
+		want := ""
+		if lf.RelPath == "foo.md" {
+			want = "patterns/foo/index.md"
+		}
+		if lf.SameURL != want {
+			t.Errorf("%s SameURL = %q, want %q", lf.RelPath, lf.SameURL, want)
+		}
+	}
+}
+
+func TestHubBuilderFollowsNav(t *testing.T) {
+	nav := domain.NewSiteNav([]domain.NavPage{
+		{Path: "patterns/index.md", Title: "Design Patterns"},
+		{Path: "patterns/zebra/index.md", Title: "Zebra Crossing"},
+		{Path: "patterns/zebra/b.md"},
+		{Path: "patterns/zebra/a.md", Title: "Alpha First"},
+		{Path: "patterns/apple/index.md"},
+	})
+	builder := NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", testCategories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "", nav, nil, nil)
+
+	docs := []*domain.Document{
+		doc([]string{"docs", "patterns", "index.md"}, "Patterns", "d", ""),
+		doc([]string{"docs", "patterns", "apple", "index.md"}, "Apple", "d", ""),
+		doc([]string{"docs", "patterns", "apple", "x.md"}, "X", "d", ""),
+		doc([]string{"docs", "patterns", "zebra", "index.md"}, "Zebra", "d", ""),
+		doc([]string{"docs", "patterns", "zebra", "a.md"}, "A", "d", ""),
+		doc([]string{"docs", "patterns", "zebra", "b.md"}, "B", "d", ""),
+		doc([]string{"docs", "patterns", "zebra", "c.md"}, "C", "d", ""),
+	}
+
+	hub, err := builder.Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if hub.Metadata.Title != "Design Patterns" {
+		t.Errorf("hub title = %q, want the nav's %q", hub.Metadata.Title, "Design Patterns")
+	}
+
+	var got []string
+	for _, group := range hub.Groups {
+		var topics []string
+		for _, topic := range group.Topics {
+			topics = append(topics, topic.Title)
+		}
+		got = append(got, group.Title+": "+strings.Join(topics, ", "))
+	}
+	// Nav order first, then anything the nav leaves out, A–Z.
+	want := []string{"Zebra Crossing: B, Alpha First, C", "Apple: X"}
+	if strings.Join(got, " | ") != strings.Join(want, " | ") {
+		t.Errorf("groups = %q, want %q", got, want)
+	}
+}
+
+func TestHubBuilderAppliesDirectives(t *testing.T) {
+	withDirectives := func(d *domain.Document, directives map[string]interface{}) *domain.Document {
+		d.Frontmatter.RawData = map[string]interface{}{"skillgen": directives}
+		return d
+	}
+
+	docs := []*domain.Document{
+		doc([]string{"docs", "patterns", "index.md"}, "Patterns", "d", ""),
+		doc([]string{"docs", "patterns", "architecture", "index.md"}, "Architecture", "d", ""),
+		withDirectives(doc([]string{"docs", "patterns", "architecture", "a.md"}, "A", "d", ""),
+			map[string]interface{}{"weight": 5}),
+		withDirectives(doc([]string{"docs", "patterns", "architecture", "z.md"}, "Z", "d", ""),
+			map[string]interface{}{"weight": -1, "title": "Zed First", "description": "Use when routing matters. More."}),
+		withDirectives(doc([]string{"docs", "patterns", "misc", "moved.md"}, "Moved", "d", ""),
+			map[string]interface{}{"group": "architecture"}),
+		withDirectives(doc([]string{"docs", "patterns", "later", "index.md"}, "Later", "d", ""),
+			map[string]interface{}{"weight": 1}),
+	}
+
+	hub, err := newTestHubBuilder().Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, group := range hub.Groups {
+		var topics []string
+		for _, topic := range group.Topics {
+			topics = append(topics, topic.Title)
+		}
+		got = append(got, group.Title+": "+strings.Join(topics, ", "))
+	}
+	want := []string{"Architecture: Zed First, Moved, A", "Later: "}
+	if strings.Join(got, " | ") != strings.Join(want, " | ") {
+		t.Errorf("groups = %q, want %q", got, want)
+	}
+
+	if topic := hub.Groups[0].Topics[0]; topic.Description != "Use when routing matters." {
+		t.Errorf("description = %q, want the directive's first sentence", topic.Description)
+	}
+}
+
+func TestHubBuilderConvertsEveryAdmonitionForm(t *testing.T) {
+	body := strings.Join([]string{
+		"# Patterns",
+		"",
+		"??? tip inline end \"Collapsed\"",
+		"    Outer.",
+		"",
+		"    !!! warning",
+		"        Nested.",
+		"",
+		"- Item",
+		"",
+		"    ???+ note \"\"",
+		"        In a list.",
+		"",
+		"```",
+		"!!! note \"Not an admonition\"",
+		"```",
+	}, "\n")
+	docs := []*domain.Document{docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "", body)}
+
+	hub, err := newTestHubBuilder().Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"> **Collapsed**",
+		">",
+		"> Outer.",
+		">",
+		"> > **Warning**",
+		"> >",
+		"> > Nested.",
+		"",
+		"- Item",
+		"",
+		"    > In a list.",
+		"",
+		"```",
+		"!!! note \"Not an admonition\"",
+		"```",
+	}, "\n")
+	if content := hub.LibraryFiles[0].Content; !strings.Contains(content, want) {
+		t.Errorf("library content = %q, want it to contain %q", content, want)
+	}
+}
+
+func TestHubBuilderRendersGitHubAlerts(t *testing.T) {
+	body := strings.Join([]string{
+		"# Patterns",
+		"",
+		"!!! danger \"Data loss\"",
+		"    Back up first.",
+		"",
+		"    !!! tip",
+		"        Nested.",
+		"",
+		"!!! note \"Note\"",
+		"    Default title.",
+		"",
+		"- Item",
+		"",
+		"    !!! warning \"In a list\"",
+		"        Stays a blockquote.",
+	}, "\n")
+	docs := []*domain.Document{docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "", body)}
+
+	hub, err := newTestHubBuilder().Build("patterns", docs, domain.PluginConfig{Description: "d", AdmonitionStyle: domain.AdmonitionGitHub})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"> [!CAUTION]",
+		"> **Data loss**",
+		">",
+		"> Back up first.",
+		">",
+		"> > **Tip**",
+		"> >",
+		"> > Nested.",
+		"",
+		"> [!NOTE]",
+		"> Default title.",
+		"",
+		"- Item",
+		"",
+		"    > **In a list**",
+		"    >",
+		"    > Stays a blockquote.",
+	}, "\n")
+	if content := hub.LibraryFiles[0].Content; !strings.Contains(content, want) {
+		t.Errorf("library content = %q, want it to contain %q", content, want)
+	}
+}
+
+func TestHubBuilderConvertsContentTabs(t *testing.T) {
+	body := strings.Join([]string{
+		"# Patterns",
+		"",
+		"=== \"GitHub Actions\"",
+		"",
+		"    ```yaml",
+		"    on: push",
+		"    ```",
+		"",
+		"===+ \"Argo\"",
+		"    !!! note",
+		"        Needs a cluster.",
+		"Done.",
+		"",
+		"!!! tip \"Variants\"",
+		"    === \"A\"",
+		"        First.",
+		"",
+		"    === \"B\"",
+		"        Second.",
+	}, "\n")
+	docs := []*domain.Document{docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "", body)}
+
+	hub, err := newTestHubBuilder().Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"**GitHub Actions**",
+		"",
+		"```yaml",
+		"on: push",
+		"```",
+		"",
+		"**Argo**",
+		"",
+		"> **Note**",
+		">",
+		"> Needs a cluster.",
+		"",
+		"Done.",
+		"",
+		"> **Variants**",
+		">",
+		"> **A**",
+		">",
+		"> First.",
+		">",
+		"> **B**",
+		">",
+		"> Second.",
+	}, "\n")
+	if content := hub.LibraryFiles[0].Content; !strings.Contains(content, want) {
+		t.Errorf("library content = %q, want it to contain %q", content, want)
+	}
+}
+
+func TestHubBuilderNormalizesMkDocsMarkup(t *testing.T) {
+	body := strings.Join([]string{
+		"# Patterns",
+		"",
+		"## Setup { #setup }",
+		"",
+		"[Get started](https://example.com/start/){ .md-button .md-button--primary } :material-check-bold: Ready :rocket:",
+		"",
+		"Version {{ config.extra.version }}, run `echo {{ x }}` at 10:30:45.",
+		"",
+		"```yaml",
+		"image: nginx # (1)!",
+		"token: ${{ secrets.TOKEN }} # pinned (2)",
+		"```",
+		"",
+		"1.  The image.",
+		"2.  The token.",
+		"",
+		"After.",
+	}, "\n")
+	docs := []*domain.Document{docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "", body)}
+	// As if the body followed three lines of frontmatter.
+	docs[0].Lines = make(domain.SourceMap, strings.Count(body, "\n")+1)
+	for i := range docs[0].Lines {
+		docs[0].Lines[i].Line = i + 4
+	}
+
+	hub, err := newTestHubBuilder().Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := strings.Join([]string{
+		"## Setup",
+		"",
+		"[Get started](https://example.com/start/) ✓ Ready 🚀",
+		"",
+		"Version {{ config.extra.version }}, run `echo {{ x }}` at 10:30:45.",
+		"",
+		"```yaml",
+		"image: nginx # (1)",
+		"token: ${{ secrets.TOKEN }} # pinned (2)",
+		"```",
+		"",
+		"1.  The image.",
+		"2.  The token.",
+		"",
+		"After.",
+	}, "\n")
+	lf := hub.LibraryFiles[0]
+	if !strings.Contains(lf.Content, want) {
+		t.Errorf("library content = %q, want it to contain %q", lf.Content, want)
+	}
+	wantFlagged := []domain.FlaggedMarkup{{Construct: domain.MarkupMacros, Text: "{{ config.extra.version }}", Position: domain.Position{Line: 10, Column: 9}}}
+	if !reflect.DeepEqual(lf.Flagged, wantFlagged) {
+		t.Errorf("flagged = %+v, want %+v", lf.Flagged, wantFlagged)
+	}
+
+	rules := domain.MarkupRules{AttrList: domain.MarkupFlag, Emoji: domain.MarkupStrip, Annotations: domain.MarkupStrip, Macros: domain.MarkupStrip}
+	hub, err = newTestHubBuilder().Build("patterns", docs, domain.PluginConfig{Description: "d", Markup: rules})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want = strings.Join([]string{
+		"## Setup { #setup }",
+		"",
+		"[Get started](https://example.com/start/){ .md-button .md-button--primary }  Ready",
+		"",
+		"Version , run `echo {{ x }}` at 10:30:45.",
+		"",
+		"```yaml",
+		"image: nginx",
+		"token: ${{ secrets.TOKEN }} # pinned",
+		"```",
+		"",
+		"After.",
+	}, "\n")
+	lf = hub.LibraryFiles[0]
+	if !strings.Contains(lf.Content, want) {
+		t.Errorf("library content = %q, want it to contain %q", lf.Content, want)
+	}
+	if len(lf.Flagged) != 2 || lf.Flagged[0].Text != "{ #setup }" {
+		t.Fatalf("flagged = %+v, want both attr lists", lf.Flagged)
+	}
+	if lf.Flagged[0].Position != (domain.Position{Line: 6, Column: 10}) || lf.Flagged[1].Position != (domain.Position{Line: 8, Column: 42}) {
+		t.Errorf("flagged at %+v and %+v, want 6:10 and 8:42", lf.Flagged[0].Position, lf.Flagged[1].Position)
+	}
+}
+
+func TestHubBuilderRewritesRelativeLinks(t *testing.T) {
+	docs := []*domain.Document{
+		docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "", strings.Join([]string{
+			"# Patterns",
+			"",
+			"See [spoke](architecture/hub-and-spoke/index.md), [its overview](architecture/hub-and-spoke/#overview),",
+			"[architecture](architecture/), [runbooks](../ops/runbooks.md#steps), ![diagram](diagram.svg),",
+			"[gone](missing.md), [elsewhere](https://example.com/x.md), and [below](#below).",
+			"",
+			"`[code](missing.md)`",
+		}, "\n")),
+		docWithBody([]string{"docs", "patterns", "architecture", "index.md"}, "Architecture Patterns", "d", "",
+			"# Architecture Patterns\n\nBack to [patterns][home].\n\n[home]: ../index.md"),
+		docWithBody([]string{"docs", "patterns", "architecture", "hub-and-spoke", "index.md"}, "Hub and Spoke", "d", "",
+			"# Hub and Spoke\n\n## Overview\n\nOne coordinator. See [trade-offs](#trade-offs).\n\n## Trade-offs\n\nScales."),
+	}
+	docs[2].Sections = []domain.Section{{Title: "Hub and Spoke", ID: "hub-and-spoke", Level: 1, SubSections: []domain.Section{
+		{Title: "Overview", ID: "overview", Level: 2},
+		{Title: "Trade-offs", ID: "trade-offs", Level: 2},
+	}}}
+	docs[0].Links = []domain.Link{
+		{Dest: "architecture/hub-and-spoke/index.md", Position: domain.Position{Line: 3, Column: 5}},
+		{Dest: "missing.md", Position: domain.Position{Line: 5, Column: 1}},
+	}
+	tree := domain.NewDocsTree([]string{
+		"patterns/index.md",
+		"patterns/diagram.svg",
+		"patterns/architecture/index.md",
+		"patterns/architecture/hub-and-spoke/index.md",
+		"ops/runbooks.md",
+	})
+	builder := NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", testCategories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "", nil, tree, nil)
+
+	hub, err := builder.Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byPath := make(map[string]domain.LibraryFile, len(hub.LibraryFiles))
+	for _, lf := range hub.LibraryFiles {
+		byPath[lf.RelPath] = lf
+	}
+
+	// Library files link to each other by relative path, and to the docs
+	// site for anything outside the hub.
+	want := strings.Join([]string{
+		"See [spoke](architecture/hub-and-spoke/index.md), [its overview](architecture/hub-and-spoke/index.md#overview),",
+		"[architecture](architecture/index.md), [runbooks](https://adaptive-enforcement-lab.com/ops/runbooks/#steps), ![diagram](https://adaptive-enforcement-lab.com/patterns/diagram.svg),",
+		"[gone](missing.md), [elsewhere](https://example.com/x.md), and [below](#below).",
+		"",
+		"`[code](missing.md)`",
+	}, "\n")
+	root := byPath["index.md"]
+	if !strings.Contains(root.Content, want) {
+		t.Errorf("root library content = %q, want it to contain %q", root.Content, want)
+	}
+	if want := []domain.Link{{Dest: "missing.md", Position: domain.Position{Line: 5, Column: 1}}}; !reflect.DeepEqual(root.BrokenLinks, want) {
+		t.Errorf("broken links = %+v, want %+v", root.BrokenLinks, want)
+	}
+	if group := byPath["architecture/index.md"]; !strings.Contains(group.Content, "[home]: ../index.md") {
+		t.Errorf("group library content = %q, want its definition relative to the library", group.Content)
+	}
+
+	// reference.md links to its own headings, numbered as GitHub numbers
+	// the second "Overview".
+	want = strings.Join([]string{
+		"See [spoke](#hub-and-spoke), [its overview](#overview-1),",
+		"[architecture](#architecture-patterns), [runbooks](https://adaptive-enforcement-lab.com/ops/runbooks/#steps), ![diagram](https://adaptive-enforcement-lab.com/patterns/diagram.svg),",
+		"[gone](missing.md), [elsewhere](https://example.com/x.md), and [below](#below).",
+	}, "\n")
+	if !strings.Contains(hub.Metadata.ReferenceBody, want) {
+		t.Errorf("root ReferenceBody = %q, want it to contain %q", hub.Metadata.ReferenceBody, want)
+	}
+	if got := hub.Groups[0].ReferenceBody; !strings.Contains(got, "[home]: #overview") {
+		t.Errorf("group ReferenceBody = %q, want its definition to point at the overview", got)
+	}
+	if got := hub.Groups[0].Topics[0].ReferenceBody; !strings.Contains(got, "[trade-offs](#trade-offs)") {
+		t.Errorf("topic ReferenceBody = %q, want its own heading's anchor", got)
+	}
+}
+
+func TestHubBuilderRewritesOverviewLinks(t *testing.T) {
+	intro := "Start with [spoke](architecture/hub-and-spoke/index.md#overview), see ![diagram](diagram.svg), " +
+		"[runbooks](../ops/runbooks.md), and [below](#below)."
+	docs := []*domain.Document{
+		docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", intro, "# Patterns\n\n"+intro),
+		docWithBody([]string{"docs", "patterns", "architecture", "hub-and-spoke", "index.md"}, "Hub and Spoke", "d", "", "# Hub and Spoke\n"),
+	}
+	tree := domain.NewDocsTree([]string{"patterns/index.md", "patterns/diagram.svg", "patterns/architecture/hub-and-spoke/index.md", "ops/runbooks.md"})
+	builder := NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", testCategories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "", nil, tree, nil)
+
+	hub, err := builder.Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "Start with [spoke](library/architecture/hub-and-spoke/index.md#overview), see ![diagram](https://adaptive-enforcement-lab.com/patterns/diagram.svg), " +
+		"[runbooks](https://adaptive-enforcement-lab.com/ops/runbooks/), and [below](library/index.md#below)."
+	if hub.Metadata.Overview != want {
+		t.Errorf("Overview = %q, want %q", hub.Metadata.Overview, want)
+	}
+}
+
+func TestHubBuilderBundlesLinkedAssets(t *testing.T) {
+	fs := filesystem.NewMemoryFileSystem()
+	fs.WriteFile("docs/patterns/a/flow.png", []byte("png"), 0644)
+	fs.WriteFile("docs/assets/policy.yaml", []byte("kind: Policy"), 0644)
+	fs.WriteFile("docs/patterns/a/big.svg", []byte(strings.Repeat("x", 13)), 0644)
+	fs.WriteFile("docs/patterns/a/bundle.zip", []byte("zip"), 0644)
+
+	root := docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "",
+		"# Patterns\n\n![Flow](a/flow.png) and [the policy](../assets/policy.yaml).")
+	root.Assets = []domain.AssetRef{{Path: "docs/patterns/a/flow.png"}, {Path: "docs/assets/policy.yaml"}}
+	topic := docWithBody([]string{"docs", "patterns", "a", "index.md"}, "A", "d", "",
+		"# A\n\n![Flow](flow.png), ![Big](big.svg), [Bundle](bundle.zip), [Gone](gone.png).")
+	topic.Assets = []domain.AssetRef{
+		{Path: "docs/patterns/a/flow.png", Position: domain.Position{Line: 3, Column: 1}},
+		{Path: "docs/patterns/a/big.svg", Position: domain.Position{Line: 3, Column: 20}},
+		{Path: "docs/patterns/a/bundle.zip", Position: domain.Position{Line: 3, Column: 37}},
+		{Path: "docs/patterns/a/gone.png", Position: domain.Position{Line: 3, Column: 59}},
+	}
+
+	builder := NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", testCategories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "", nil, nil, fs)
+	hub, err := builder.Build("patterns", []*domain.Document{root, topic}, domain.PluginConfig{Description: "d", Assets: domain.AssetRules{MaxSize: 12}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Each asset is bundled once, at its path under the category, or under
+	// _assets/ if it lives elsewhere in the docs.
+	want := []domain.LibraryAsset{
+		{RelPath: "a/flow.png", Content: []byte("png"), SourcePath: "docs/patterns/a/flow.png"},
+		{RelPath: "_assets/assets/policy.yaml", Content: []byte("kind: Policy"), SourcePath: "docs/assets/policy.yaml"},
+	}
+	if !reflect.DeepEqual(hub.Assets, want) {
+		t.Errorf("assets = %+v, want %+v", hub.Assets, want)
+	}
+
+	if got := hub.LibraryFiles[0].Content; !strings.Contains(got, "![Flow](a/flow.png) and [the policy](_assets/assets/policy.yaml).") {
+		t.Errorf("root library content = %q, want links to the bundled copies", got)
+	}
+	if got := hub.Metadata.ReferenceBody; !strings.Contains(got, "![Flow](library/a/flow.png) and [the policy](library/_assets/assets/policy.yaml).") {
+		t.Errorf("root ReferenceBody = %q, want links into library/", got)
+	}
+
+	// Skipped assets fall back to the docs site; a missing one is left to
+	// the broken link check.
+	lf := hub.LibraryFiles[1]
+	if !strings.Contains(lf.Content, "![Flow](flow.png), ![Big](https://adaptive-enforcement-lab.com/patterns/a/big.svg), [Bundle](https://adaptive-enforcement-lab.com/patterns/a/bundle.zip), [Gone]") {
+		t.Errorf("topic library content = %q, want skipped assets linked upstream", lf.Content)
+	}
+	if len(lf.Skipped) != 2 || lf.Skipped[0].Path != "patterns/a/big.svg" || lf.Skipped[1].Path != "patterns/a/bundle.zip" {
+		t.Fatalf("skipped = %+v, want big.svg and bundle.zip", lf.Skipped)
+	}
+	if lf.Skipped[0].Position != topic.Assets[1].Position || lf.Skipped[1].Position != topic.Assets[2].Position {
+		t.Errorf("skipped at %+v and %+v, want where the doc links to each", lf.Skipped[0].Position, lf.Skipped[1].Position)
+	}
+}
+
+func TestHubBuilderBundlesAssetsInsideTabsAndAdmonitions(t *testing.T) {
+	fs := filesystem.NewMemoryFileSystem()
+	fs.WriteFile("docs/patterns/tab.png", []byte("png"), 0644)
+	fs.WriteFile("docs/patterns/policy.yaml", []byte("kind: Policy"), 0644)
+	fs.WriteFile("docs/patterns/index.md", []byte("---\ntitle: Patterns\ndescription: d\n---\n# Patterns\n\n"+
+		"=== \"Argo\"\n\n    ![Flow](tab.png)\n\n!!! note\n    Get [the policy](policy.yaml).\n"), 0644)
+
+	reader := filesystem.NewDocumentReader(fs, parser.NewFrontmatterParser(), parser.NewMarkdownParser(), parser.NewSectionParser(),
+		parser.NewContentExtractor(), filesystem.NewSnippetResolver(fs, []string{"docs"}), testCategories)
+	root, err := reader.ReadDocument("docs/patterns/index.md")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	builder := NewHubBuilder(NewTopicExtractor(DefaultBaseURL, "docs", testCategories), parser.NewAdmonitionConverter(), parser.NewTabConverter(), parser.NewMarkupNormalizer(), parser.NewLinkRewriter(), DefaultBaseURL, "docs", "", nil, nil, fs)
+	hub, err := builder.Build("patterns", []*domain.Document{root}, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, asset := range hub.Assets {
+		got = append(got, asset.RelPath)
+	}
+	if want := []string{"tab.png", "policy.yaml"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("bundled %q, want %q", got, want)
+	}
+	if body := hub.Metadata.ReferenceBody; !strings.Contains(body, "![Flow](library/tab.png)") || !strings.Contains(body, "[the policy](library/policy.yaml)") {
+		t.Errorf("ReferenceBody = %q, want links into library/", body)
+	}
+}
+
+func TestHubBuilderLinksToHeadingsByTheirSiteIDs(t *testing.T) {
+	docs := []*domain.Document{
+		docWithBody([]string{"docs", "patterns", "index.md"}, "Patterns", "d", "",
+			"# Patterns\n\nSee [setup](#custom) and [usage](#usage_1).\n\n## Setup { #custom }\n\nSteps.\n\n=== \"Tab\"\n    ## Variants\n\n## Usage\n\n## Usage\n\nRun it."),
+	}
+	docs[0].Sections = []domain.Section{{Title: "Patterns", ID: "patterns", Level: 1, SubSections: []domain.Section{
+		{Title: "Setup", ID: "custom", Level: 2},
+		{Title: "Usage", ID: "usage", Level: 2},
+		{Title: "Usage", ID: "usage_1", Level: 2},
+	}}}
+
+	hub, err := newTestHubBuilder().Build("patterns", docs, domain.PluginConfig{Description: "d"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The tab's heading is no section of its own, and GitHub numbers a
+	// repeat from 1 where MkDocs adds "_1".
+	if got := hub.Metadata.ReferenceBody; !strings.Contains(got, "See [setup](#setup) and [usage](#usage-1).") {
+		t.Errorf("ReferenceBody = %q, want links to the reference.md anchors", got)
+	}
+}
@@ -0,0 +1,387 @@
+package extractor
+
+import (
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+// urlScheme matches the scheme of an absolute URL, such as "https:" or
+// "mailto:".
+var urlScheme = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*:`)
+
+// inlineLink matches a markdown link, for its text.
+var inlineLink = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+
+// hubLinks resolves the links in a hub's docs against the hub and the docs
+// tree. Library files link to each other by relative path, reference.md
+// to its own headings, and both to the docs site for anything else.
+type hubLinks struct {
+	baseURL  string
+	docsRoot string
+	category domain.Category
+	tree     *domain.DocsTree
+
+	// docs holds the hub's docs by their path relative to the docs root,
+	// and assets the library path of each asset bundled with them.
+	docs   map[string]*domain.Document
+	assets map[string]string
+
+	// anchors holds the reference.md anchor of each doc's heading, and
+	// headingAnchors the anchor of each heading inside its body, by the
+	// id MkDocs gives that heading.
+	anchors        map[string]string
+	headingAnchors map[string]map[string]string
+}
+
+// linkTarget is where a link points: a doc in the hub, an asset bundled
+// with it, or a URL on the docs site, any with the link's fragment. keep is
+// set for a link that needs no rewriting, such as one to another site.
+type linkTarget struct {
+	doc      *domain.Document
+	asset    string
+	url      string
+	fragment string
+	keep     bool
+}
+
+func newHubLinks(baseURL, docsRoot string, category domain.Category, tree *domain.DocsTree, docs []*domain.Document) *hubLinks {
+	l := &hubLinks{
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		docsRoot:       docsRoot,
+		category:       category,
+		tree:           tree,
+		docs:           make(map[string]*domain.Document, len(docs)),
+		assets:         make(map[string]string),
+		anchors:        make(map[string]string),
+		headingAnchors: make(map[string]map[string]string),
+	}
+	for _, doc := range docs {
+		l.docs[l.docsRel(doc.Path)] = doc
+	}
+	return l
+}
+
+// docsRel returns the slash-separated path of the doc at docPath relative
+// to the docs root.
+func (l *hubLinks) docsRel(docPath string) string {
+	return docsRelPath(l.docsRoot, docPath)
+}
+
+// resolve works out where dest, a link in the doc at from, points. It
+// reports false for a link to nothing under the docs root. Without a docs
+// tree, a link that leaves the hub is taken to point at a real page.
+func (l *hubLinks) resolve(from *domain.Document, dest string) (linkTarget, bool) {
+	if dest == "" || strings.HasPrefix(dest, "#") || strings.HasPrefix(dest, "//") || urlScheme.MatchString(dest) {
+		return linkTarget{keep: true}, true
+	}
+
+	target, fragment, _ := strings.Cut(dest, "#")
+	if strings.HasPrefix(target, "/") {
+		return linkTarget{url: l.baseURL + target, fragment: fragment}, true
+	}
+
+	rel := path.Join(path.Dir(l.docsRel(from.Path)), target)
+	if rel == ".." || strings.HasPrefix(rel, "../") {
+		return linkTarget{}, false
+	}
+
+	// MkDocs serves a page as a directory, so a link may name the
+	// directory instead of the page.
+	candidates := []string{rel}
+	if strings.HasSuffix(target, "/") || path.Ext(target) == "" {
+		candidates = []string{path.Join(rel, "index.md"), rel + ".md"}
+	}
+
+	for _, c := range candidates {
+		if doc, ok := l.docs[c]; ok {
+			return linkTarget{doc: doc, fragment: fragment}, true
+		}
+		if asset, ok := l.assets[unescapePath(c)]; ok {
+			return linkTarget{asset: asset, fragment: fragment}, true
+		}
+	}
+	for _, c := range candidates {
+		if l.tree == nil || l.tree.Has(c) || l.tree.Has(unescapePath(c)) {
+			return linkTarget{url: l.siteURL(c), fragment: fragment}, true
+		}
+	}
+	return linkTarget{}, false
+}
+
+// siteURL returns the docs site URL of the file at rel, relative to the
+// docs root: the directory a page is served as, or the file itself.
+func (l *hubLinks) siteURL(rel string) string {
+	if !strings.EqualFold(path.Ext(rel), ".md") {
+		return l.baseURL + "/" + rel
+	}
+	page := strings.TrimSuffix(rel, path.Ext(rel))
+	if strings.EqualFold(path.Base(page), "index") {
+		page = path.Dir(page)
+	}
+	if page == "." {
+		return l.baseURL + "/"
+	}
+	return l.baseURL + "/" + page + "/"
+}
+
+// libraryDest rewrites dest, a link in doc's library file, to the library
+// file or asset it names or to the docs site, and reports false if it
+// points at nothing.
+func (l *hubLinks) libraryDest(doc *domain.Document, dest string) (string, bool) {
+	target, ok := l.resolve(doc, dest)
+	switch {
+	case !ok:
+		return dest, false
+	case target.keep:
+		return dest, true
+	case target.doc != nil || target.asset != "":
+		from := path.Dir(libraryRelPath(l.docsRel(doc.Path), l.category))
+		to := target.asset
+		if target.doc != nil {
+			to = libraryRelPath(l.docsRel(target.doc.Path), l.category)
+		}
+		rel, err := filepath.Rel(filepath.FromSlash(from), filepath.FromSlash(to))
+		if err != nil {
+			return dest, true
+		}
+		return withFragment(escapePath(filepath.ToSlash(rel)), target.fragment), true
+	default:
+		return withFragment(target.url, target.fragment), true
+	}
+}
+
+// skillDest rewrites dest, a link in the overview SKILL.md takes from doc,
+// to the library file or asset it names or to the docs site. A link to a
+// heading on doc itself goes to doc's library file.
+func (l *hubLinks) skillDest(doc *domain.Document, dest string) string {
+	if strings.HasPrefix(dest, "#") {
+		return escapePath("library/"+libraryRelPath(l.docsRel(doc.Path), l.category)) + dest
+	}
+
+	target, ok := l.resolve(doc, dest)
+	switch {
+	case !ok || target.keep:
+		return dest
+	case target.asset != "":
+		return withFragment(escapePath("library/"+target.asset), target.fragment)
+	case target.doc != nil:
+		return withFragment(escapePath("library/"+libraryRelPath(l.docsRel(target.doc.Path), l.category)), target.fragment)
+	default:
+		return withFragment(target.url, target.fragment)
+	}
+}
+
+// referenceDest rewrites dest, a link in doc's body in reference.md, to the
+// heading it names there or to the docs site. Links that point at nothing
+// are reported from the library file, and left as they are here.
+func (l *hubLinks) referenceDest(doc *domain.Document, dest string) string {
+	if strings.HasPrefix(dest, "#") {
+		if anchor, ok := l.headingAnchors[l.docsRel(doc.Path)][dest[1:]]; ok {
+			return "#" + anchor
+		}
+		return dest
+	}
+
+	target, ok := l.resolve(doc, dest)
+	switch {
+	case !ok || target.keep:
+		return dest
+	case target.asset != "":
+		return withFragment(escapePath("library/"+target.asset), target.fragment)
+	case target.doc != nil:
+		rel := l.docsRel(target.doc.Path)
+		if anchor, ok := l.headingAnchors[rel][target.fragment]; ok && target.fragment != "" {
+			return "#" + anchor
+		}
+		if anchor, ok := l.anchors[rel]; ok {
+			return "#" + anchor
+		}
+		return withFragment(l.siteURL(rel), target.fragment)
+	default:
+		return withFragment(target.url, target.fragment)
+	}
+}
+
+// linkPosition returns where the first link in links to dest, as written,
+// is, marking it in placed so a second link to dest is placed at the next.
+// Markdown conversion moves links about, so they are matched by
+// destination rather than by where they end up.
+func linkPosition(links []domain.Link, placed []bool, dest string) domain.Position {
+	for i, link := range links {
+		if !placed[i] && (link.Dest == dest || link.Dest == unescapePath(dest)) {
+			placed[i] = true
+			return link.Position
+		}
+	}
+	return domain.Position{}
+}
+
+// unescapePath decodes the percent-escapes in a link's path, such as "%20"
+// for a space, to the file name they stand for.
+func unescapePath(p string) string {
+	if unescaped, err := url.PathUnescape(p); err == nil {
+		return unescaped
+	}
+	return p
+}
+
+// escapePath percent-escapes a file's path for use as a link destination.
+func escapePath(p string) string {
+	return (&url.URL{Path: p}).EscapedPath()
+}
+
+// withFragment appends fragment to dest, if there is one.
+func withFragment(dest, fragment string) string {
+	if fragment == "" {
+		return dest
+	}
+	return dest + "#" + fragment
+}
+
+// anchorHub records the reference.md anchors of every heading in hub, in
+// the order reference.tmpl renders them: the page title, the overview and
+// the root doc's body, then each group and its body, each followed by its
+// topics and theirs. GitHub numbers repeated headings in that order, so
+// the anchors have to be worked out in it too.
+func (l *hubLinks) anchorHub(hub *domain.Skill) {
+	var s slugger
+	s.anchor(hub.Metadata.Title + " — Full Reference")
+	l.anchorDoc(&s, hub.Metadata.SourcePath, "Overview", hub.Metadata.ReferenceBody)
+	for _, group := range hub.Groups {
+		l.anchorDoc(&s, group.SourcePath, group.Title, group.ReferenceBody)
+		for _, topic := range group.Topics {
+			l.anchorDoc(&s, topic.SourcePath, topic.Title, topic.ReferenceBody)
+		}
+	}
+}
+
+// anchorDoc records the anchor of the heading a doc's body sits under in
+// reference.md, and of each heading inside the body under the id its
+// section has on the site. A body heading is matched to the next section
+// with the same text, and one that matches none, such as a heading a tab's
+// body brings out, is passed over. sourcePath is empty for a group
+// without a doc of its own.
+func (l *hubLinks) anchorDoc(s *slugger, sourcePath, title, body string) {
+	anchor := s.anchor(title)
+	if sourcePath == "" {
+		for _, heading := range bodyHeadings(body) {
+			s.anchor(heading)
+		}
+		return
+	}
+
+	rel := l.docsRel(sourcePath)
+	l.anchors[rel] = anchor
+	var sections []domain.Section
+	if doc, ok := l.docs[rel]; ok {
+		sections = flattenSections(doc.Sections)
+	}
+	ids := make(map[string]string)
+	for _, heading := range bodyHeadings(body) {
+		anchor := s.anchor(heading)
+		for i, section := range sections {
+			if slug(section.Title) == slug(heading) {
+				ids[section.ID] = anchor
+				sections = sections[i+1:]
+				break
+			}
+		}
+	}
+	l.headingAnchors[rel] = ids
+}
+
+// rewriteReference rewrites the links in every reference body of hub.
+func (l *hubLinks) rewriteReference(hub *domain.Skill, rewrite func(doc *domain.Document, body string) string) {
+	body := func(sourcePath, content string) string {
+		if doc, ok := l.docs[l.docsRel(sourcePath)]; ok && sourcePath != "" {
+			return rewrite(doc, content)
+		}
+		return content
+	}
+	hub.Metadata.ReferenceBody = body(hub.Metadata.SourcePath, hub.Metadata.ReferenceBody)
+	for i := range hub.Groups {
+		group := &hub.Groups[i]
+		group.ReferenceBody = body(group.SourcePath, group.ReferenceBody)
+		for j := range group.Topics {
+			topic := &group.Topics[j]
+			topic.ReferenceBody = body(topic.SourcePath, topic.ReferenceBody)
+		}
+	}
+}
+
+// bodyHeadings returns the text of each ATX heading in body, outside
+// fenced code.
+func bodyHeadings(body string) []string {
+	var headings []string
+	var fence string
+	for _, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		case isHeading(trimmed):
+			text := strings.TrimSpace(strings.TrimRight(trimmed[headingLevel(trimmed):], "# "))
+			headings = append(headings, inlineLink.ReplaceAllString(text, "$1"))
+		}
+	}
+	return headings
+}
+
+// flattenSections returns sections and all their subsections in document
+// order.
+func flattenSections(sections []domain.Section) []domain.Section {
+	var flat []domain.Section
+	for _, section := range sections {
+		flat = append(flat, section)
+		flat = append(flat, flattenSections(section.SubSections)...)
+	}
+	return flat
+}
+
+// slugger gives headings GitHub's anchors: lowercased, with punctuation
+// dropped and spaces turned to hyphens, and "-1", "-2", and so on added to
+// a repeat.
+type slugger struct {
+	seen map[string]int
+}
+
+// anchor returns the anchor of the next heading with text.
+func (s *slugger) anchor(text string) string {
+	if s.seen == nil {
+		s.seen = make(map[string]int)
+	}
+	slug := slug(text)
+
+	n := s.seen[slug]
+	s.seen[slug] = n + 1
+	if n > 0 {
+		return slug + "-" + strconv.Itoa(n)
+	}
+	return slug
+}
+
+// slug returns GitHub's anchor for a heading with text, before any count
+// is added for a repeat.
+func slug(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.Is(unicode.M, r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
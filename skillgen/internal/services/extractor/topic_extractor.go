@@ -2,18 +2,28 @@ package extractor
 
 import (
 	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
 
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
 )
 
+// DefaultBaseURL is the published AEL docs site that source links point at.
+const DefaultBaseURL = "https://adaptive-enforcement-lab.com"
+
 // TopicExtractor implements ports.TopicExtractor.
-type TopicExtractor struct{}
+type TopicExtractor struct {
+	baseURL    string
+	docsRoot   string
+	categories domain.CategorySet
+}
 
-// NewTopicExtractor creates a new topic extractor.
-func NewTopicExtractor() *TopicExtractor {
-	return &TopicExtractor{}
+// NewTopicExtractor creates a new topic extractor whose URLs are built on
+// baseURL, the docs site root. A document is only a topic if it lies under
+// one of categories' source directories, relative to docsRoot.
+func NewTopicExtractor(baseURL, docsRoot string, categories domain.CategorySet) *TopicExtractor {
+	return &TopicExtractor{baseURL: baseURL, docsRoot: docsRoot, categories: categories}
 }
 
 // Extract derives a Topic from a document's frontmatter and path. Every AEL
@@ -31,88 +41,102 @@ func (e *TopicExtractor) Extract(doc *domain.Document) (*domain.Topic, error) {
 		description = firstSentence(doc.Introduction)
 	}
 
-	category := determineCategoryFromPath(doc.Path)
-	if category == "" {
+	rel := docsRelPath(e.docsRoot, doc.Path)
+	category, ok := e.determineCategoryFromPath(rel)
+	if !ok {
 		return nil, fmt.Errorf("cannot determine category from path: %s", doc.Path)
 	}
 
 	return &domain.Topic{
 		Title:       title,
 		Description: description,
-		URL:         buildSourceURL(doc.Path, category),
-		LibraryPath: buildLibraryPath(doc.Path, category),
+		URL:         buildSourceURL(e.baseURL, rel, category),
+		LibraryPath: "library/" + libraryRelPath(rel, category),
+		SourcePath:  doc.Path,
 	}, nil
 }
 
-// buildLibraryPath mirrors HubBuilder.libraryFile's RelPath computation, so
-// a topic's SKILL.md link points at the exact file HubBuilder writes under
-// library/ for the same doc.
-func buildLibraryPath(path, category string) string {
-	segments := categorySegments(path, category)
-	if len(segments) == 0 {
-		return "library/index.md"
+// libraryRelPath is a doc's path under library/, given its path relative
+// to the docs root. It mirrors the doc's own path under the category's
+// source directory: index.md stays index.md in its directory, and a page
+// keeps its filename beside it. Topics link to it and
+// HubBuilder.libraryFile writes it, so the two always agree.
+func libraryRelPath(rel string, category domain.Category) string {
+	_, dirs, _ := domain.CategorySet{category}.Locate(rel)
+	name := "index.md"
+	if pageName(rel) != "" {
+		name = path.Base(rel)
 	}
-	return "library/" + strings.Join(segments, "/") + "/index.md"
+	return strings.Join(append(dirs[:len(dirs):len(dirs)], name), "/")
 }
 
-// determineCategoryFromPath extracts the category from the file path.
-func determineCategoryFromPath(path string) string {
-	cleanPath := filepath.Clean(path)
-	parts := strings.Split(cleanPath, string(filepath.Separator))
-
-	for _, part := range parts {
-		if domain.IsCategory(part) {
-			return part
-		}
+// docsRelPath is a doc's slash-separated path relative to docsRoot, as
+// mkdocs.yml's nav and Category.Locate name it. Only the part under the
+// docs root says which category a doc is in: the directories above it may
+// be named anything, a category included.
+func docsRelPath(docsRoot, docPath string) string {
+	rel, err := filepath.Rel(docsRoot, docPath)
+	if err != nil {
+		return filepath.ToSlash(docPath)
 	}
+	return filepath.ToSlash(rel)
+}
 
-	return ""
+// determineCategoryFromPath finds the category whose source directory
+// contains rel, a path relative to the docs root.
+func (e *TopicExtractor) determineCategoryFromPath(rel string) (domain.Category, bool) {
+	category, _, ok := e.categories.Locate(rel)
+	return category, ok
 }
 
-// buildSourceURL constructs the URL to the source documentation.
+// buildSourceURL constructs the URL to the source documentation from a
+// doc's path relative to the docs root.
 //
 // The docs site mirrors the directory layout, so every segment from the
-// category directory down to the document's parent must be preserved.
-// Example: /docs/patterns/efficiency/idempotency/index.md
+// category's source directory down to the document's parent must be
+// preserved.
+// Example: patterns/efficiency/idempotency/index.md
 //
 //	-> /patterns/efficiency/idempotency/
-func buildSourceURL(path string, category string) string {
-	baseURL := "https://adaptive-enforcement-lab.com"
-
-	parts := strings.Split(filepath.Clean(path), string(filepath.Separator))
-
-	// Drop the filename; MkDocs serves index.md as its parent directory.
-	if len(parts) > 0 && strings.HasSuffix(parts[len(parts)-1], ".md") {
-		parts = parts[:len(parts)-1]
-	}
+//
+// and patterns/efficiency/idempotency/checkpoints.md
+//
+//	-> /patterns/efficiency/idempotency/checkpoints/
+func buildSourceURL(baseURL, rel string, category domain.Category) string {
+	baseURL = strings.TrimSuffix(baseURL, "/")
 
-	for i, part := range parts {
-		if part == category {
-			return fmt.Sprintf("%s/%s/", baseURL, strings.Join(parts[i:], "/"))
-		}
+	if _, _, ok := (domain.CategorySet{category}).Locate(rel); !ok {
+		return baseURL
 	}
+	segments := categorySegments(rel, category)
 
-	return baseURL
+	return fmt.Sprintf("%s/%s/", baseURL, strings.Join(append([]string{category.SourceDir}, segments...), "/"))
 }
 
-// categorySegments returns the path segments strictly between the category
-// directory and the document filename, e.g. for
-// docs/patterns/architecture/hub-and-spoke/index.md with category "patterns"
-// it returns ["architecture", "hub-and-spoke"].
-func categorySegments(path, category string) []string {
-	parts := strings.Split(filepath.Clean(path), string(filepath.Separator))
-
-	if len(parts) > 0 && strings.HasSuffix(parts[len(parts)-1], ".md") {
-		parts = parts[:len(parts)-1]
+// categorySegments returns the segments of a document's URL under the
+// category's source directory, as MkDocs serves it, given its path relative
+// to the docs root. For patterns/architecture/hub-and-spoke/index.md with
+// category "patterns" it returns ["architecture", "hub-and-spoke"]; a page
+// other than index.md is served as a directory of its own, so
+// patterns/architecture/hub-and-spoke/tradeoffs.md gives
+// ["architecture", "hub-and-spoke", "tradeoffs"].
+func categorySegments(rel string, category domain.Category) []string {
+	_, segments, _ := domain.CategorySet{category}.Locate(rel)
+	if page := pageName(rel); page != "" {
+		segments = append(segments[:len(segments):len(segments)], page)
 	}
+	return segments
+}
 
-	for i, part := range parts {
-		if part == category {
-			return parts[i+1:]
-		}
+// pageName returns the URL segment a page other than index.md adds to its
+// directory's ("tradeoffs" for tradeoffs.md), or "" for index.md, which
+// MkDocs serves as the directory itself.
+func pageName(path string) string {
+	base := filepath.Base(filepath.FromSlash(path))
+	if strings.EqualFold(base, "index.md") {
+		return ""
 	}
-
-	return nil
+	return strings.TrimSuffix(base, filepath.Ext(base))
 }
 
 // maxTopicDescriptionWords caps a topic's one-line description so a hub with
@@ -16,7 +16,7 @@ func TestTopicExtractorUsesFrontmatter(t *testing.T) {
 		},
 	}
 
-	topic, err := NewTopicExtractor().Extract(doc)
+	topic, err := NewTopicExtractor(DefaultBaseURL, "docs", testCategories).Extract(doc)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -48,7 +48,7 @@ func TestTopicExtractorFallsBackToIntroductionSentence(t *testing.T) {
 		Introduction: "These patterns govern structure. They also govern behavior.",
 	}
 
-	topic, err := NewTopicExtractor().Extract(doc)
+	topic, err := NewTopicExtractor(DefaultBaseURL, "docs", testCategories).Extract(doc)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -62,7 +62,7 @@ func TestTopicExtractorFallsBackToIntroductionSentence(t *testing.T) {
 func TestTopicExtractorRejectsEmptyTitle(t *testing.T) {
 	doc := &domain.Document{Path: filepath.Join("docs", "patterns", "index.md")}
 
-	if _, err := NewTopicExtractor().Extract(doc); err == nil {
+	if _, err := NewTopicExtractor(DefaultBaseURL, "docs", testCategories).Extract(doc); err == nil {
 		t.Fatal("expected an error for an empty title")
 	}
 }
@@ -73,7 +73,44 @@ func TestTopicExtractorRejectsUnknownCategory(t *testing.T) {
 		Frontmatter: domain.Frontmatter{Title: "Something"},
 	}
 
-	if _, err := NewTopicExtractor().Extract(doc); err == nil {
+	if _, err := NewTopicExtractor(DefaultBaseURL, "docs", testCategories).Extract(doc); err == nil {
 		t.Fatal("expected an error when the path has no known category segment")
 	}
 }
+
+func TestTopicExtractorUsesConfiguredBaseURL(t *testing.T) {
+	doc := &domain.Document{
+		Path:        filepath.Join("docs", "build", "go-cli", "index.md"),
+		Frontmatter: domain.Frontmatter{Title: "Go CLI"},
+	}
+
+	topic, err := NewTopicExtractor("https://staging.example.com/", "docs", testCategories).Extract(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "https://staging.example.com/build/go-cli/"
+	if topic.URL != want {
+		t.Errorf("URL = %q, want %q", topic.URL, want)
+	}
+}
+
+func TestTopicExtractorIgnoresCategoryNamesAboveTheDocsRoot(t *testing.T) {
+	docsRoot := filepath.Join("tmp", "build", "docs")
+	doc := &domain.Document{
+		Path:        filepath.Join(docsRoot, "patterns", "architecture", "index.md"),
+		Frontmatter: domain.Frontmatter{Title: "Architecture Patterns"},
+	}
+
+	topic, err := NewTopicExtractor(DefaultBaseURL, docsRoot, testCategories).Extract(doc)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if want := DefaultBaseURL + "/patterns/architecture/"; topic.URL != want {
+		t.Errorf("URL = %q, want %q", topic.URL, want)
+	}
+	if want := "library/architecture/index.md"; topic.LibraryPath != want {
+		t.Errorf("LibraryPath = %q, want %q", topic.LibraryPath, want)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// unownedExempt matches files under the output directory that another tool
+// maintains: release-please writes each plugin's CHANGELOG.md.
+var unownedExempt = []string{"*/CHANGELOG.md"}
+
+// NewGeneratedManifest builds the manifest written to manifestPath. outputs
+// maps each generated file to the SHA-256 of its content; sources maps a
+// generated file to the document it was built from, and sourceHashes each
+// document to the SHA-256 of its content. Paths are recorded relative to
+// the manifest's directory and the docs root respectively, so the manifest
+// is the same wherever the repo and the docs are checked out.
+func NewGeneratedManifest(
+	manifestPath, docsRoot string,
+	outputs, sources, sourceHashes map[string]string,
+) (*domain.GeneratedManifest, error) {
+	manifestDir := filepath.Dir(manifestPath)
+	manifest := &domain.GeneratedManifest{Files: []domain.GeneratedFile{}}
+
+	for path, hash := range outputs {
+		rel, err := relPath(manifestDir, path)
+		if err != nil {
+			return nil, err
+		}
+		file := domain.GeneratedFile{Path: rel, SHA256: hash}
+
+		if source, ok := sources[path]; ok {
+			if file.Source, err = relPath(docsRoot, source); err != nil {
+				return nil, err
+			}
+			file.SourceSHA256 = sourceHashes[source]
+		}
+
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	sort.Slice(manifest.Files, func(i, j int) bool { return manifest.Files[i].Path < manifest.Files[j].Path })
+	return manifest, nil
+}
+
+// relPath returns path relative to base, slash-separated.
+func relPath(base, path string) (string, error) {
+	absBase, err := filepath.Abs(base)
+	if err != nil {
+		return "", err
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(absBase, absPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to make %s relative to %s: %w", path, base, err)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// ManifestVerifier checks the output on disk against the generated-files
+// manifest. Unlike DriftChecker it needs neither the docs nor a
+// regeneration: it only answers whether the files are still exactly what
+// skillgen last wrote.
+type ManifestVerifier struct {
+	fs ports.FileSystem
+}
+
+// NewManifestVerifier creates a verifier that reads the output from fs.
+func NewManifestVerifier(fs ports.FileSystem) *ManifestVerifier {
+	return &ManifestVerifier{fs: fs}
+}
+
+// Verify reports every listed file that is missing (DriftMissing) or whose
+// content no longer matches its checksum (DriftModified), and every file
+// under outputDir the manifest does not list (DriftUnexpected). Results
+// are sorted by path.
+func (v *ManifestVerifier) Verify(manifest *domain.GeneratedManifest, manifestPath, outputDir string) ([]domain.FileDrift, error) {
+	var drifts []domain.FileDrift
+	manifestDir := filepath.Dir(manifestPath)
+
+	owned := make(map[string]bool, len(manifest.Files)+1)
+	if abs, err := filepath.Abs(manifestPath); err == nil {
+		owned[abs] = true
+	}
+
+	for _, file := range manifest.Files {
+		path := filepath.Join(manifestDir, filepath.FromSlash(file.Path))
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		owned[abs] = true
+
+		if !v.fs.Exists(path) {
+			drifts = append(drifts, domain.FileDrift{Path: path, Kind: domain.DriftMissing})
+			continue
+		}
+		content, err := v.fs.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		if HashContent(content) != file.SHA256 {
+			drifts = append(drifts, domain.FileDrift{Path: path, Kind: domain.DriftModified})
+		}
+	}
+
+	files, err := listFiles(v.fs, outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", outputDir, err)
+	}
+	for _, path := range files {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if owned[abs] || exempt(outputDir, path) {
+			continue
+		}
+		drifts = append(drifts, domain.FileDrift{Path: path, Kind: domain.DriftUnexpected})
+	}
+
+	sort.Slice(drifts, func(i, j int) bool { return drifts[i].Path < drifts[j].Path })
+	return drifts, nil
+}
+
+// exempt reports whether path, under outputDir, is maintained by another
+// tool and so is not expected in the manifest.
+func exempt(outputDir, path string) bool {
+	rel, err := filepath.Rel(outputDir, path)
+	if err != nil {
+		return false
+	}
+	for _, pattern := range unownedExempt {
+		if ok, _ := filepath.Match(pattern, filepath.ToSlash(rel)); ok {
+			return true
+		}
+	}
+	return false
+}
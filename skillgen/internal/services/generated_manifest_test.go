@@ -0,0 +1,70 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+func TestNewGeneratedManifest(t *testing.T) {
+	outputs := map[string]string{
+		"repo/plugins/patterns/skills/patterns/library/index.md": "lib-hash",
+		"repo/README.md": "readme-hash",
+	}
+	sources := map[string]string{
+		"repo/plugins/patterns/skills/patterns/library/index.md": "/docs/patterns/index.md",
+	}
+	sourceHashes := map[string]string{"/docs/patterns/index.md": "doc-hash"}
+
+	manifest, err := NewGeneratedManifest("repo/generated.json", "/docs", outputs, sources, sourceHashes)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.GeneratedFile{
+		{Path: "README.md", SHA256: "readme-hash"},
+		{Path: "plugins/patterns/skills/patterns/library/index.md", SHA256: "lib-hash", Source: "patterns/index.md", SourceSHA256: "doc-hash"},
+	}
+	if len(manifest.Files) != len(want) {
+		t.Fatalf("expected %d files, got %+v", len(want), manifest.Files)
+	}
+	for i := range want {
+		if manifest.Files[i] != want[i] {
+			t.Errorf("file %d: expected %+v, got %+v", i, want[i], manifest.Files[i])
+		}
+	}
+}
+
+func TestManifestVerifier_Verify(t *testing.T) {
+	fs := filesystem.NewMemoryFileSystem()
+	fs.WriteFile("repo/plugins/a/skills/a/SKILL.md", []byte("generated"), 0644)
+	fs.WriteFile("repo/plugins/a/skills/a/reference.md", []byte("edited by hand"), 0644)
+	fs.WriteFile("repo/plugins/a/skills/a/notes.md", []byte("not generated"), 0644)
+	fs.WriteFile("repo/plugins/a/CHANGELOG.md", []byte("release-please"), 0644)
+
+	manifest := &domain.GeneratedManifest{Files: []domain.GeneratedFile{
+		{Path: "plugins/a/skills/a/SKILL.md", SHA256: HashContent([]byte("generated"))},
+		{Path: "plugins/a/skills/a/reference.md", SHA256: HashContent([]byte("generated"))},
+		{Path: "plugins/a/.claude-plugin/plugin.json", SHA256: HashContent([]byte("{}"))},
+	}}
+
+	drifts, err := NewManifestVerifier(fs).Verify(manifest, "repo/generated.json", "repo/plugins")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []domain.FileDrift{
+		{Path: "repo/plugins/a/.claude-plugin/plugin.json", Kind: domain.DriftMissing},
+		{Path: "repo/plugins/a/skills/a/notes.md", Kind: domain.DriftUnexpected},
+		{Path: "repo/plugins/a/skills/a/reference.md", Kind: domain.DriftModified},
+	}
+	if len(drifts) != len(want) {
+		t.Fatalf("expected %d drifts, got %+v", len(want), drifts)
+	}
+	for i := range want {
+		if drifts[i] != want[i] {
+			t.Errorf("drift %d: expected %+v, got %+v", i, want[i], drifts[i])
+		}
+	}
+}
@@ -2,7 +2,6 @@ package services
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 
@@ -84,14 +83,9 @@ func (g *MarketplaceGenerator) Generate(
 		manifest := buildPluginManifest(pluginKey, &pluginConfig, &metadata.Common, version)
 		pluginPath := filepath.Join(outputDir, pluginKey, ".claude-plugin", "plugin.json")
 
-		// Ensure directory exists
-		pluginDir := filepath.Dir(pluginPath)
-		g.logger.Debug("creating plugin directory", "path", pluginDir)
-
-		if err := os.MkdirAll(pluginDir, 0755); err != nil {
-			return fmt.Errorf("failed to create plugin directory %s: %w", pluginDir, err)
-		}
-
+		// The writer's filesystem creates parent directories itself, so
+		// nothing here touches disk directly and an in-memory run stays
+		// in memory.
 		if err := g.writer.WritePluginManifest(manifest, pluginPath); err != nil {
 			return fmt.Errorf("failed to write plugin manifest for %s: %w", pluginKey, err)
 		}
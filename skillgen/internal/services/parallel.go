@@ -0,0 +1,34 @@
+package services
+
+import "sync"
+
+// RunBounded calls fn once for every index in [0, n), on at most jobs
+// goroutines at a time, and returns when every call has. fn must only write
+// state owned by its own index; callers that need ordered results collect
+// them into a slice by index.
+func RunBounded(jobs, n int, fn func(i int)) {
+	if jobs < 1 {
+		jobs = 1
+	}
+	if jobs > n {
+		jobs = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < jobs; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
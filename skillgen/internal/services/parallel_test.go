@@ -0,0 +1,53 @@
+package services
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestRunBounded(t *testing.T) {
+	tests := []struct {
+		name string
+		jobs int
+		n    int
+	}{
+		{"serial", 1, 10},
+		{"more items than jobs", 3, 50},
+		{"more jobs than items", 8, 2},
+		{"zero jobs runs serially", 0, 5},
+		{"no items", 4, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var running, peak int32
+			results := make([]int, tt.n)
+
+			RunBounded(tt.jobs, tt.n, func(i int) {
+				now := atomic.AddInt32(&running, 1)
+				for {
+					old := atomic.LoadInt32(&peak)
+					if now <= old || atomic.CompareAndSwapInt32(&peak, old, now) {
+						break
+					}
+				}
+				results[i] = i * i
+				atomic.AddInt32(&running, -1)
+			})
+
+			for i, got := range results {
+				if got != i*i {
+					t.Errorf("results[%d] = %d, want %d", i, got, i*i)
+				}
+			}
+
+			limit := int32(tt.jobs)
+			if limit < 1 {
+				limit = 1
+			}
+			if peak > limit {
+				t.Errorf("peak concurrency = %d, want at most %d", peak, limit)
+			}
+		})
+	}
+}
@@ -2,23 +2,32 @@ package services
 
 import (
 	"fmt"
+	"io/fs"
 	"testing"
 
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
 )
 
 type MockTemplateRenderer struct {
-	readmeData    *domain.ReadmeData
-	renderContent string
-	renderError   error
+	readmeData       *domain.ReadmeData
+	renderContent    string
+	renderError      error
+	skillContent     string
+	referenceContent string
 }
 
 func (m *MockTemplateRenderer) RenderSkill(skill *domain.Skill) (string, error) {
-	return "", fmt.Errorf("not implemented in mock")
+	if m.skillContent == "" {
+		return "", fmt.Errorf("not implemented in mock")
+	}
+	return m.skillContent, nil
 }
 
 func (m *MockTemplateRenderer) RenderReference(skill *domain.Skill) (string, error) {
-	return "", fmt.Errorf("not implemented in mock")
+	if m.referenceContent == "" {
+		return "", fmt.Errorf("not implemented in mock")
+	}
+	return m.referenceContent, nil
 }
 
 func (m *MockTemplateRenderer) RenderReadme(data *domain.ReadmeData) (string, error) {
@@ -52,11 +61,12 @@ func (m *MockFileSystem) WriteFile(path string, data []byte, perm int) error {
 	return nil
 }
 
-func (m *MockFileSystem) MkdirAll(path string, perm int) error  { return nil }
-func (m *MockFileSystem) Glob(pattern string) ([]string, error) { return nil, nil }
-func (m *MockFileSystem) Exists(path string) bool               { return false }
-func (m *MockFileSystem) IsDir(path string) bool                { return false }
-func (m *MockFileSystem) RemoveAll(path string) error           { return nil }
+func (m *MockFileSystem) MkdirAll(path string, perm int) error         { return nil }
+func (m *MockFileSystem) Glob(pattern string) ([]string, error)        { return nil, nil }
+func (m *MockFileSystem) Exists(path string) bool                      { return false }
+func (m *MockFileSystem) IsDir(path string) bool                       { return false }
+func (m *MockFileSystem) RemoveAll(path string) error                  { return nil }
+func (m *MockFileSystem) WalkDir(root string, fn fs.WalkDirFunc) error { return nil }
 
 func testHub(category, title, description string) *domain.Skill {
 	return &domain.Skill{
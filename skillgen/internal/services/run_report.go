@@ -0,0 +1,220 @@
+package services
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// FailureClass identifies the pipeline stage a failure came from. Each class
+// has its own exit code so CI can tell a broken docs checkout from a
+// template bug without parsing logs.
+type FailureClass string
+
+const (
+	FailureDiscovery   FailureClass = "discovery"
+	FailureParse       FailureClass = "parse"
+	FailureValidation  FailureClass = "validation"
+	FailureWrite       FailureClass = "write"
+	FailureMarketplace FailureClass = "marketplace"
+	FailureReadme      FailureClass = "readme"
+)
+
+// Exit codes. 2 is left to the flag package, which exits with it on a usage
+// error. Failure classes are numbered in pipeline order.
+const (
+	ExitOK          = 0
+	ExitDrift       = 1
+	ExitDiscovery   = 3
+	ExitParse       = 4
+	ExitValidation  = 5
+	ExitWrite       = 6
+	ExitMarketplace = 7
+	ExitReadme      = 8
+)
+
+// failureOrder lists every class in pipeline order, with its exit code.
+var failureOrder = []struct {
+	class FailureClass
+	code  int
+}{
+	{FailureDiscovery, ExitDiscovery},
+	{FailureParse, ExitParse},
+	{FailureValidation, ExitValidation},
+	{FailureWrite, ExitWrite},
+	{FailureMarketplace, ExitMarketplace},
+	{FailureReadme, ExitReadme},
+}
+
+// FailPolicy decides which findings make a run exit non-zero.
+type FailPolicy string
+
+const (
+	// FailNever exits 0 unless output failed to render or write (drift in
+	// check mode aside).
+	FailNever FailPolicy = "never"
+	// FailOnError exits non-zero on any error.
+	FailOnError FailPolicy = "error"
+	// FailOnWarning exits non-zero on any error or warning.
+	FailOnWarning FailPolicy = "warning"
+)
+
+// ParseFailPolicy validates a --fail-on value.
+func ParseFailPolicy(value string) (FailPolicy, error) {
+	switch p := FailPolicy(value); p {
+	case FailNever, FailOnError, FailOnWarning:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid fail policy %q: must be never, error, or warning", value)
+}
+
+// Failure is one recorded problem: what stage it came from, how serious it
+// is, and the document or output file it concerns, with the 1-based line
+// and column in it when known.
+type Failure struct {
+	Class    FailureClass
+	Severity ports.Severity
+	Path     string
+	Line     int
+	Column   int
+	Message  string
+
+	// wholeHub marks an error that kept a whole hub from being built.
+	wholeHub bool
+}
+
+// RunReport collects failures across a generation run, so main can keep
+// going past a bad document and still decide at the end how the run should
+// exit and which documents to point at.
+type RunReport struct {
+	failures []Failure
+}
+
+// NewRunReport creates an empty run report.
+func NewRunReport() *RunReport {
+	return &RunReport{}
+}
+
+// Error records an error-severity failure.
+func (r *RunReport) Error(class FailureClass, path string, err error) {
+	r.failures = append(r.failures, Failure{Class: class, Severity: ports.SeverityError, Path: path, Message: err.Error()})
+}
+
+// HubError records an error-severity failure that kept a whole hub from
+// being built. Output missing a hub is incomplete, so it is withheld just
+// as output that failed to render or write is.
+func (r *RunReport) HubError(class FailureClass, path string, err error) {
+	r.failures = append(r.failures, Failure{Class: class, Severity: ports.SeverityError, Path: path, Message: err.Error(), wholeHub: true})
+}
+
+// AddValidation records skill validator findings at their own severity.
+func (r *RunReport) AddValidation(findings []ports.ValidationError) {
+	for _, f := range findings {
+		r.failures = append(r.failures, Failure{
+			Class:    FailureValidation,
+			Severity: f.Severity,
+			Path:     f.File,
+			Line:     f.Line,
+			Column:   f.Column,
+			Message:  f.Message,
+		})
+	}
+}
+
+// Merge appends every failure recorded in other, in order. Work done in
+// parallel records into its own report, merged back in a fixed order so the
+// summary never depends on scheduling.
+func (r *RunReport) Merge(other *RunReport) {
+	r.failures = append(r.failures, other.failures...)
+}
+
+// Errors returns the number of error-severity failures.
+func (r *RunReport) Errors() int {
+	return r.count(ports.SeverityError)
+}
+
+// Warnings returns the number of warning-severity failures.
+func (r *RunReport) Warnings() int {
+	return r.count(ports.SeverityWarning)
+}
+
+// Failures returns every recorded failure in the order it was recorded.
+func (r *RunReport) Failures() []Failure {
+	return r.failures
+}
+
+// ExitCode returns the exit code for the run under policy. When several
+// classes qualify, the earliest pipeline stage wins: a discovery failure
+// usually explains whatever went wrong after it. A hub that failed to build
+// and output that failed to render or write always count, so a run that
+// left the output unchanged never looks like a success.
+func (r *RunReport) ExitCode(policy FailPolicy) int {
+	if policy == FailNever {
+		return r.OutputExitCode()
+	}
+
+	for _, entry := range failureOrder {
+		for _, f := range r.failures {
+			if f.Class != entry.class {
+				continue
+			}
+			if f.Severity == ports.SeverityError || policy == FailOnWarning {
+				return entry.code
+			}
+		}
+	}
+
+	return ExitOK
+}
+
+// OutputExitCode returns the exit code of the earliest error that failed a
+// whole hub or a stage that renders or writes output, or ExitOK if there is
+// none. Output with such an error is incomplete, so it is not committed
+// whatever the fail policy; any other error only leaves out the document it
+// concerns.
+func (r *RunReport) OutputExitCode() int {
+	for _, entry := range failureOrder {
+		for _, f := range r.failures {
+			if f.Class != entry.class || f.Severity != ports.SeverityError {
+				continue
+			}
+			if f.wholeHub || entry.code >= ExitWrite {
+				return entry.code
+			}
+		}
+	}
+	return ExitOK
+}
+
+// WriteSummary lists every failure, one per line, so the offending
+// documents are visible at the end of a long log.
+func (r *RunReport) WriteSummary(w io.Writer) {
+	if len(r.failures) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w, "\n=== Failures ===")
+	for _, f := range r.failures {
+		path := f.Path
+		switch {
+		case path == "":
+			path = "-"
+		case f.Line > 0 && f.Column > 0:
+			path = fmt.Sprintf("%s:%d:%d", path, f.Line, f.Column)
+		case f.Line > 0:
+			path = fmt.Sprintf("%s:%d", path, f.Line)
+		}
+		fmt.Fprintf(w, "[%s] %s %s: %s\n", f.Class, f.Severity, path, f.Message)
+	}
+}
+
+func (r *RunReport) count(sev ports.Severity) int {
+	n := 0
+	for _, f := range r.failures {
+		if f.Severity == sev {
+			n++
+		}
+	}
+	return n
+}
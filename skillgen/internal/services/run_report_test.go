@@ -0,0 +1,111 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+func TestParseFailPolicy(t *testing.T) {
+	for _, value := range []string{"never", "error", "warning"} {
+		if _, err := ParseFailPolicy(value); err != nil {
+			t.Errorf("ParseFailPolicy(%q) returned error: %v", value, err)
+		}
+	}
+	if _, err := ParseFailPolicy("sometimes"); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
+
+func TestRunReport_ExitCode(t *testing.T) {
+	warning := []ports.ValidationError{{Severity: ports.SeverityWarning, Message: "short description", File: "docs/build/index.md"}}
+
+	tests := []struct {
+		name   string
+		record func(*RunReport)
+		policy FailPolicy
+		want   int
+	}{
+		{"clean run", func(r *RunReport) {}, FailOnWarning, ExitOK},
+		{"never ignores errors", func(r *RunReport) {
+			r.Error(FailureParse, "docs/a/index.md", fmt.Errorf("bad yaml"))
+		}, FailNever, ExitOK},
+		{"never still counts output that failed to write", func(r *RunReport) {
+			r.Error(FailureParse, "docs/a/index.md", fmt.Errorf("bad yaml"))
+			r.Error(FailureWrite, "plugins/build", fmt.Errorf("disk full"))
+		}, FailNever, ExitWrite},
+		{"never still counts a hub that failed to build", func(r *RunReport) {
+			r.HubError(FailureDiscovery, "docs/secure", fmt.Errorf("no such directory"))
+			r.Error(FailureReadme, "README.md", fmt.Errorf("render failed"))
+		}, FailNever, ExitDiscovery},
+		{"error policy ignores warnings", func(r *RunReport) {
+			r.AddValidation(warning)
+		}, FailOnError, ExitOK},
+		{"warning policy counts warnings", func(r *RunReport) {
+			r.AddValidation(warning)
+		}, FailOnWarning, ExitValidation},
+		{"each class has its own code", func(r *RunReport) {
+			r.Error(FailureReadme, "README.md", fmt.Errorf("render failed"))
+		}, FailOnError, ExitReadme},
+		{"earliest stage wins", func(r *RunReport) {
+			r.Error(FailureMarketplace, ".claude-plugin/marketplace.json", fmt.Errorf("write failed"))
+			r.Error(FailureDiscovery, "docs/secure", fmt.Errorf("permission denied"))
+		}, FailOnError, ExitDiscovery},
+		{"warning in earlier stage does not mask a later error under error policy", func(r *RunReport) {
+			r.AddValidation(warning)
+			r.Error(FailureWrite, "plugins/build", fmt.Errorf("disk full"))
+		}, FailOnError, ExitWrite},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := NewRunReport()
+			tt.record(report)
+			if got := report.ExitCode(tt.policy); got != tt.want {
+				t.Errorf("ExitCode(%s) = %d, want %d", tt.policy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunReport_CountsAndSummary(t *testing.T) {
+	report := NewRunReport()
+	report.Error(FailureParse, "docs/patterns/broken/index.md", fmt.Errorf("invalid frontmatter"))
+	report.AddValidation([]ports.ValidationError{
+		{Severity: ports.SeverityError, Message: "description is required", File: "docs/build/index.md"},
+		{Severity: ports.SeverityWarning, Message: "no source URL"},
+		{Severity: ports.SeverityWarning, Message: `link "gone.md" does not resolve`, File: "docs/ops/index.md", Line: 12, Column: 5},
+		{Severity: ports.SeverityWarning, Message: `snippet "x.md" not found`, File: "docs/ops/index.md", Line: 3},
+	})
+
+	if report.Errors() != 2 || report.Warnings() != 3 {
+		t.Errorf("Errors() = %d, Warnings() = %d, want 2 and 3", report.Errors(), report.Warnings())
+	}
+
+	var buf bytes.Buffer
+	report.WriteSummary(&buf)
+	out := buf.String()
+
+	for _, want := range []string{
+		"[parse] error docs/patterns/broken/index.md: invalid frontmatter",
+		"[validation] error docs/build/index.md: description is required",
+		"[validation] warning -: no source URL",
+		`[validation] warning docs/ops/index.md:12:5: link "gone.md" does not resolve`,
+		`[validation] warning docs/ops/index.md:3: snippet "x.md" not found`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunReport_EmptySummary(t *testing.T) {
+	var buf bytes.Buffer
+	NewRunReport().WriteSummary(&buf)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output for a clean run, got %q", buf.String())
+	}
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// sourceCommitNote matches the docs commit HubBuilder records at the end of
+// a library file's source note.
+var sourceCommitNote = regexp.MustCompile(`(?m)^(Source: \S+) \(docs commit [0-9a-f]+\)$`)
+
+// KeepSourceCommit returns what to write at path in place of content: the
+// file already on disk when the two differ only in the docs commit their
+// source notes record, content otherwise. A library file so records the
+// commit its content last changed at, and a run at a new docs commit
+// rewrites only the files whose docs changed.
+func KeepSourceCommit(disk ports.FileSystem, path string, content []byte) []byte {
+	if !disk.Exists(path) {
+		return content
+	}
+	current, err := disk.ReadFile(path)
+	if err != nil {
+		return content
+	}
+	if bytes.Equal(withoutSourceCommit(current), withoutSourceCommit(content)) {
+		return current
+	}
+	return content
+}
+
+// withoutSourceCommit drops the docs commit from content's source note.
+func withoutSourceCommit(content []byte) []byte {
+	return sourceCommitNote.ReplaceAll(content, []byte("$1"))
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/adapters/filesystem"
+)
+
+func TestKeepSourceCommit(t *testing.T) {
+	const path = "plugins/patterns/skills/patterns/library/index.md"
+	old := "# Patterns\n\nSource: https://example.com/patterns/ (docs commit 1111111)\nBody.\n"
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"only the commit changed", "# Patterns\n\nSource: https://example.com/patterns/ (docs commit 2222222)\nBody.\n", old},
+		{"no commit this run", "# Patterns\n\nSource: https://example.com/patterns/\nBody.\n", old},
+		{"content changed", "# Patterns\n\nSource: https://example.com/patterns/ (docs commit 2222222)\nNew body.\n", "# Patterns\n\nSource: https://example.com/patterns/ (docs commit 2222222)\nNew body.\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			disk := filesystem.NewMemoryFileSystem()
+			disk.WriteFile(path, []byte(old), 0644)
+			if got := string(KeepSourceCommit(disk, path, []byte(tt.content))); got != tt.want {
+				t.Errorf("KeepSourceCommit = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := string(KeepSourceCommit(filesystem.NewMemoryFileSystem(), path, []byte(old))); got != old {
+		t.Errorf("KeepSourceCommit without a file on disk = %q, want the content as is", got)
+	}
+}
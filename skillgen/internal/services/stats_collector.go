@@ -0,0 +1,55 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// StatsCollector measures a hub skill as it would be written: SKILL.md and
+// reference.md are rendered through the same templates as a real run, so
+// the counts match the files on disk.
+type StatsCollector struct {
+	renderer ports.TemplateRenderer
+}
+
+// NewStatsCollector creates a stats collector.
+func NewStatsCollector(renderer ports.TemplateRenderer) *StatsCollector {
+	return &StatsCollector{renderer: renderer}
+}
+
+// Collect renders a hub and counts its topics, words, and bytes.
+func (c *StatsCollector) Collect(hub *domain.Skill) (domain.HubStats, error) {
+	skill, err := c.renderer.RenderSkill(hub)
+	if err != nil {
+		return domain.HubStats{}, fmt.Errorf("failed to render SKILL.md for %s: %w", hub.Metadata.Name, err)
+	}
+	reference, err := c.renderer.RenderReference(hub)
+	if err != nil {
+		return domain.HubStats{}, fmt.Errorf("failed to render reference.md for %s: %w", hub.Metadata.Name, err)
+	}
+
+	stats := domain.HubStats{
+		Category:       hub.Metadata.Category,
+		Documents:      len(hub.LibraryFiles),
+		Groups:         len(hub.Groups),
+		SkillWords:     len(strings.Fields(skill)),
+		SkillBytes:     len(skill),
+		ReferenceWords: len(strings.Fields(reference)),
+		ReferenceBytes: len(reference),
+	}
+	for _, group := range hub.Groups {
+		stats.Topics += len(group.Topics)
+	}
+	for _, lf := range hub.LibraryFiles {
+		stats.LibraryWords += len(strings.Fields(lf.Content))
+		stats.LibraryBytes += len(lf.Content)
+	}
+	for _, asset := range hub.Assets {
+		stats.LibraryBytes += len(asset.Content)
+	}
+
+	return stats, nil
+}
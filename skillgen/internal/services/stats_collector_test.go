@@ -0,0 +1,55 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+)
+
+func TestStatsCollector_Collect(t *testing.T) {
+	renderer := &MockTemplateRenderer{
+		skillContent:     "# Patterns\n\nThree word overview.",
+		referenceContent: "# Reference\n",
+	}
+
+	hub := &domain.Skill{
+		Metadata: domain.SkillMetadata{Name: "patterns", Category: "patterns"},
+		Groups: []domain.TopicGroup{
+			{Title: "Architecture", Topics: []domain.Topic{{Title: "Hub and Spoke"}, {Title: "Strangler Fig"}}},
+			{Title: "Efficiency", Topics: []domain.Topic{{Title: "Idempotency"}}},
+		},
+		LibraryFiles: []domain.LibraryFile{
+			{RelPath: "index.md", Content: "one two"},
+			{RelPath: "architecture/index.md", Content: "three"},
+		},
+	}
+
+	stats, err := NewStatsCollector(renderer).Collect(hub)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := domain.HubStats{
+		Category:       "patterns",
+		Documents:      2,
+		Groups:         2,
+		Topics:         3,
+		SkillWords:     5,
+		SkillBytes:     len(renderer.skillContent),
+		ReferenceWords: 2,
+		ReferenceBytes: len(renderer.referenceContent),
+		LibraryWords:   3,
+		LibraryBytes:   len("one two") + len("three"),
+	}
+	if stats != want {
+		t.Errorf("Collect() = %+v, want %+v", stats, want)
+	}
+}
+
+func TestStatsCollector_RenderError(t *testing.T) {
+	hub := &domain.Skill{Metadata: domain.SkillMetadata{Name: "patterns"}}
+
+	if _, err := NewStatsCollector(&MockTemplateRenderer{}).Collect(hub); err == nil {
+		t.Fatal("expected an error when SKILL.md fails to render")
+	}
+}
@@ -0,0 +1,116 @@
+package validator
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// MetadataValidator implements ports.MetadataValidator.
+type MetadataValidator struct {
+	metadataPath string
+	manifestPath string
+}
+
+// NewMetadataValidator creates a metadata validator. The paths are only used
+// to attribute findings to the file that needs fixing.
+func NewMetadataValidator(metadataPath, manifestPath string) *MetadataValidator {
+	return &MetadataValidator{metadataPath: metadataPath, manifestPath: manifestPath}
+}
+
+// Validate cross-checks plugin-metadata.json, the release manifest, and the
+// categories skills are generated for. A category without metadata cannot
+// be built at all, and an invalid or overlapping sourceDir builds the wrong
+// pages, so those are errors; the rest degrade the published marketplace
+// and are warnings. versions may be nil when the release
+// manifest could not be read, in which case version checks are skipped.
+func (v *MetadataValidator) Validate(metadata *domain.PluginMetadata, versions map[string]string, categories []string) []ports.ValidationError {
+	if metadata == nil {
+		return []ports.ValidationError{{
+			Severity: ports.SeverityError,
+			Message:  "plugin metadata is nil",
+			File:     v.metadataPath,
+		}}
+	}
+
+	var findings []ports.ValidationError
+	add := func(sev ports.Severity, file, format string, args ...any) {
+		findings = append(findings, ports.ValidationError{
+			Severity: sev,
+			Message:  fmt.Sprintf(format, args...),
+			File:     file,
+		})
+	}
+
+	isCategory := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		isCategory[category] = true
+		if _, ok := metadata.Plugins[category]; !ok {
+			add(ports.SeverityError, v.metadataPath, "no plugin-metadata.json entry for category %q", category)
+		}
+	}
+
+	// Map iteration order is random; sort so findings are stable.
+	keys := make([]string, 0, len(metadata.Plugins))
+	for key := range metadata.Plugins {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var sourceDirs []domain.Category
+	for _, key := range keys {
+		cfg := metadata.Plugins[key]
+		if !isCategory[key] {
+			add(ports.SeverityWarning, v.metadataPath, "plugin %q is not among the generated categories; it is published without a hub skill", key)
+		}
+		if category := domain.NewCategory(key, cfg); isRelativeDir(category.SourceDir) {
+			sourceDirs = append(sourceDirs, category)
+		} else {
+			add(ports.SeverityError, v.metadataPath, "plugin %q sourceDir %q must be a directory inside the docs root", key, cfg.SourceDir)
+		}
+		if err := (domain.PageRules{Include: cfg.Include, Exclude: cfg.Exclude}).Validate(); err != nil {
+			add(ports.SeverityError, v.metadataPath, "plugin %q: %v", key, err)
+		}
+		if err := cfg.Markup.Validate(); err != nil {
+			add(ports.SeverityError, v.metadataPath, "plugin %q: %v", key, err)
+		}
+		if err := cfg.Assets.Validate(); err != nil {
+			add(ports.SeverityError, v.metadataPath, "plugin %q: %v", key, err)
+		}
+		if !cfg.AdmonitionStyle.Valid() {
+			add(ports.SeverityError, v.metadataPath, "plugin %q admonitionStyle %q must be %q or %q", key, cfg.AdmonitionStyle, domain.AdmonitionBlockquote, domain.AdmonitionGitHub)
+		}
+		if cfg.Description == "" {
+			add(ports.SeverityError, v.metadataPath, "plugin %q has no description", key)
+		}
+		if cfg.Category == "" {
+			add(ports.SeverityWarning, v.metadataPath, "plugin %q has no marketplace category", key)
+		}
+		if versions != nil && versions[fmt.Sprintf("plugins/%s", key)] == "" {
+			add(ports.SeverityWarning, v.manifestPath, "no release version for plugin %q; it is published as 0.0.0", key)
+		}
+	}
+
+	// Every page belongs to exactly one collection, so no source directory
+	// may contain another.
+	for i, a := range sourceDirs {
+		for _, b := range sourceDirs[i+1:] {
+			if a.SourceDir == b.SourceDir || strings.HasPrefix(b.SourceDir, a.SourceDir+"/") || strings.HasPrefix(a.SourceDir, b.SourceDir+"/") {
+				add(ports.SeverityError, v.metadataPath, "plugins %q and %q have overlapping sourceDirs %q and %q", a.Name, b.Name, a.SourceDir, b.SourceDir)
+			}
+		}
+	}
+
+	return findings
+}
+
+// isRelativeDir reports whether dir, a slash-separated sourceDir, stays
+// inside the docs root.
+func isRelativeDir(dir string) bool {
+	clean := path.Clean(dir)
+	return clean == dir && clean != "." && clean != ".." && !strings.HasPrefix(clean, "../")
+}
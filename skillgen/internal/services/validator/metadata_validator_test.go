@@ -0,0 +1,195 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+func validMetadata() *domain.PluginMetadata {
+	return &domain.PluginMetadata{
+		Plugins: map[string]domain.PluginConfig{
+			"patterns": {Description: "Use when designing automation.", Category: "development"},
+			"build":    {Description: "Use when building a Go CLI.", Category: "devops"},
+		},
+	}
+}
+
+func validVersions() map[string]string {
+	return map[string]string{"plugins/patterns": "1.0.0", "plugins/build": "1.0.0"}
+}
+
+func TestMetadataValidatorAcceptsConsistentConfig(t *testing.T) {
+	v := NewMetadataValidator("plugin-metadata.json", ".release-please-manifest.json")
+	if errs := v.Validate(validMetadata(), validVersions(), []string{"patterns", "build"}); len(errs) != 0 {
+		t.Errorf("expected no findings, got %v", errs)
+	}
+}
+
+func TestMetadataValidatorFindings(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(*domain.PluginMetadata, map[string]string)
+		categories []string
+		wantSev    ports.Severity
+		wantSub    string
+		wantFile   string
+	}{
+		{
+			name:       "category without metadata",
+			categories: []string{"patterns", "build", "secure"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `no plugin-metadata.json entry for category "secure"`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name:       "plugin without category",
+			categories: []string{"patterns"},
+			wantSev:    ports.SeverityWarning,
+			wantSub:    `plugin "build" is not among the generated categories`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "sourceDir outside the docs root",
+			mutate: func(m *domain.PluginMetadata, _ map[string]string) {
+				cfg := m.Plugins["build"]
+				cfg.SourceDir = "../elsewhere"
+				m.Plugins["build"] = cfg
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `plugin "build" sourceDir "../elsewhere" must be a directory inside the docs root`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "malformed exclude pattern",
+			mutate: func(m *domain.PluginMetadata, _ map[string]string) {
+				cfg := m.Plugins["build"]
+				cfg.Exclude = []string{"drafts/[a-"}
+				m.Plugins["build"] = cfg
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `plugin "build": invalid pattern "drafts/[a-"`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "unknown admonition style",
+			mutate: func(m *domain.PluginMetadata, _ map[string]string) {
+				cfg := m.Plugins["build"]
+				cfg.AdmonitionStyle = "html"
+				m.Plugins["build"] = cfg
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `plugin "build" admonitionStyle "html" must be "blockquote" or "github"`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "unknown markup action",
+			mutate: func(m *domain.PluginMetadata, _ map[string]string) {
+				cfg := m.Plugins["build"]
+				cfg.Markup.Emoji = "drop"
+				m.Plugins["build"] = cfg
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `plugin "build": markup emoji "drop" must be "strip", "plain", or "flag"`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "negative asset size cap",
+			mutate: func(m *domain.PluginMetadata, _ map[string]string) {
+				cfg := m.Plugins["build"]
+				cfg.Assets.MaxSize = -1
+				m.Plugins["build"] = cfg
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `plugin "build": assets maxSize -1 must not be negative`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "shared sourceDir",
+			mutate: func(m *domain.PluginMetadata, _ map[string]string) {
+				cfg := m.Plugins["patterns"]
+				cfg.SourceDir = "build"
+				m.Plugins["patterns"] = cfg
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `plugins "build" and "patterns" have overlapping sourceDirs "build" and "build"`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "nested sourceDir",
+			mutate: func(m *domain.PluginMetadata, _ map[string]string) {
+				cfg := m.Plugins["patterns"]
+				cfg.SourceDir = "build/patterns"
+				m.Plugins["patterns"] = cfg
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `plugins "build" and "patterns" have overlapping sourceDirs "build" and "build/patterns"`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "empty description",
+			mutate: func(m *domain.PluginMetadata, _ map[string]string) {
+				m.Plugins["build"] = domain.PluginConfig{Category: "devops"}
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityError,
+			wantSub:    `plugin "build" has no description`,
+			wantFile:   "plugin-metadata.json",
+		},
+		{
+			name: "missing release version",
+			mutate: func(_ *domain.PluginMetadata, v map[string]string) {
+				delete(v, "plugins/build")
+			},
+			categories: []string{"patterns", "build"},
+			wantSev:    ports.SeverityWarning,
+			wantSub:    `no release version for plugin "build"`,
+			wantFile:   ".release-please-manifest.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, versions := validMetadata(), validVersions()
+			if tt.mutate != nil {
+				tt.mutate(metadata, versions)
+			}
+
+			errs := NewMetadataValidator("plugin-metadata.json", ".release-please-manifest.json").
+				Validate(metadata, versions, tt.categories)
+			for _, e := range errs {
+				if strings.Contains(e.Message, tt.wantSub) {
+					if e.Severity != tt.wantSev {
+						t.Errorf("severity = %s, want %s", e.Severity, tt.wantSev)
+					}
+					if e.File != tt.wantFile {
+						t.Errorf("file = %q, want %q", e.File, tt.wantFile)
+					}
+					return
+				}
+			}
+			t.Errorf("expected a finding containing %q, got %v", tt.wantSub, errs)
+		})
+	}
+}
+
+func TestMetadataValidatorSkipsVersionsWhenManifestUnavailable(t *testing.T) {
+	errs := NewMetadataValidator("plugin-metadata.json", ".release-please-manifest.json").
+		Validate(validMetadata(), nil, []string{"patterns", "build"})
+	if len(errs) != 0 {
+		t.Errorf("expected no findings without a manifest, got %v", errs)
+	}
+}
+
+func TestMetadataValidatorSatisfiesPortInterface(t *testing.T) {
+	var _ ports.MetadataValidator = NewMetadataValidator("", "")
+}
@@ -0,0 +1,47 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+// NavValidator implements ports.NavValidator.
+type NavValidator struct {
+	navPath string
+}
+
+// NewNavValidator creates a nav validator. navPath, the mkdocs.yml the nav
+// was read from, is only used to attribute findings.
+func NewNavValidator(navPath string) *NavValidator {
+	return &NavValidator{navPath: navPath}
+}
+
+// Validate reports each nav entry whose page does not exist. MkDocs itself
+// only warns about these, and the generator just has nothing to include, so
+// they are warnings.
+func (v *NavValidator) Validate(nav *domain.SiteNav, exists func(path string) bool) []ports.ValidationError {
+	if nav == nil {
+		return nil
+	}
+
+	var findings []ports.ValidationError
+	for _, page := range nav.Pages {
+		if exists(page.Path) {
+			continue
+		}
+		message := fmt.Sprintf("nav entry points at %s, which does not exist", page.Path)
+		if page.Title != "" {
+			message = fmt.Sprintf("nav entry %q points at %s, which does not exist", page.Title, page.Path)
+		}
+		findings = append(findings, ports.ValidationError{
+			Severity: ports.SeverityWarning,
+			Message:  message,
+			File:     v.navPath,
+			Line:     page.Line,
+		})
+	}
+
+	return findings
+}
@@ -0,0 +1,34 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/domain"
+	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
+)
+
+func TestNavValidatorReportsMissingPages(t *testing.T) {
+	nav := domain.NewSiteNav([]domain.NavPage{
+		{Path: "patterns/index.md", Line: 3},
+		{Path: "patterns/gone.md", Title: "Gone", Line: 4},
+	})
+	exists := func(path string) bool { return path == "patterns/index.md" }
+
+	findings := NewNavValidator("mkdocs.yml").Validate(nav, exists)
+	if len(findings) != 1 {
+		t.Fatalf("expected one finding, got %v", findings)
+	}
+	want := ports.ValidationError{
+		Severity: ports.SeverityWarning,
+		Message:  `nav entry "Gone" points at patterns/gone.md, which does not exist`,
+		File:     "mkdocs.yml",
+		Line:     4,
+	}
+	if findings[0] != want {
+		t.Errorf("expected %+v, got %+v", want, findings[0])
+	}
+
+	if findings := NewNavValidator("mkdocs.yml").Validate(nil, exists); len(findings) != 0 {
+		t.Errorf("expected no findings without a nav, got %v", findings)
+	}
+}
@@ -32,11 +32,14 @@ const (
 var namePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 
 // SkillValidator implements ports.SkillValidator.
-type SkillValidator struct{}
+type SkillValidator struct {
+	categories domain.CategorySet
+}
 
-// NewSkillValidator creates a new skill validator.
-func NewSkillValidator() *SkillValidator {
-	return &SkillValidator{}
+// NewSkillValidator creates a new skill validator that accepts the given
+// categories.
+func NewSkillValidator(categories domain.CategorySet) *SkillValidator {
+	return &SkillValidator{categories: categories}
 }
 
 // Validate checks a skill against Claude Code's requirements.
@@ -78,7 +81,7 @@ func (v *SkillValidator) Validate(skill *domain.Skill) []ports.ValidationError {
 		add(ports.SeverityWarning, "no overview or topic groups extracted; the skill body will be near-empty")
 	}
 
-	if !domain.IsCategory(skill.Metadata.Category) {
+	if !v.categories.Contains(skill.Metadata.Category) {
 		add(ports.SeverityError, "unknown category %q", skill.Metadata.Category)
 	}
 
@@ -86,6 +89,46 @@ func (v *SkillValidator) Validate(skill *domain.Skill) []ports.ValidationError {
 		add(ports.SeverityWarning, "no source URL: the skill cannot link back to its documentation")
 	}
 
+	// Markup the plugin's rules flag, links to nothing, assets left out of
+	// the library, and pages the site can't reach are reported against the
+	// doc they came from, where they are fixed.
+	for _, lf := range skill.LibraryFiles {
+		if lf.SameURL != "" {
+			findings = append(findings, ports.ValidationError{
+				Severity: ports.SeverityWarning,
+				Message:  fmt.Sprintf("page has the same URL on the site as %s, so MkDocs builds only one of them", lf.SameURL),
+				File:     lf.SourcePath,
+			})
+		}
+		for _, f := range lf.Flagged {
+			findings = append(findings, ports.ValidationError{
+				Severity: ports.SeverityWarning,
+				Message:  fmt.Sprintf("MkDocs-only markup (%s) %q left in the generated markdown", f.Construct, f.Text),
+				File:     lf.SourcePath,
+				Line:     f.Line,
+				Column:   f.Column,
+			})
+		}
+		for _, link := range lf.BrokenLinks {
+			findings = append(findings, ports.ValidationError{
+				Severity: ports.SeverityWarning,
+				Message:  fmt.Sprintf("link %q does not resolve to a page or file under the docs root", link.Dest),
+				File:     lf.SourcePath,
+				Line:     link.Line,
+				Column:   link.Column,
+			})
+		}
+		for _, asset := range lf.Skipped {
+			findings = append(findings, ports.ValidationError{
+				Severity: ports.SeverityWarning,
+				Message:  fmt.Sprintf("asset %s is not bundled, so its link points at the docs site: %s", asset.Path, asset.Reason),
+				File:     lf.SourcePath,
+				Line:     asset.Line,
+				Column:   asset.Column,
+			})
+		}
+	}
+
 	return findings
 }
 
@@ -8,6 +8,11 @@ import (
 	"github.com/adaptive-enforcement-lab/claude-skills/skillgen/internal/ports"
 )
 
+var testCategories = domain.CategorySet{
+	{Name: "patterns", SourceDir: "patterns"},
+	{Name: "enforce", SourceDir: "enforce"},
+}
+
 func validSkill() *domain.Skill {
 	return &domain.Skill{
 		Metadata: domain.SkillMetadata{
@@ -32,7 +37,7 @@ func messages(errs []ports.ValidationError, sev ports.Severity) []string {
 }
 
 func TestValidateAcceptsWellFormedSkill(t *testing.T) {
-	if errs := NewSkillValidator().Validate(validSkill()); len(errs) != 0 {
+	if errs := NewSkillValidator(testCategories).Validate(validSkill()); len(errs) != 0 {
 		t.Errorf("expected no findings, got %d: %v", len(errs), errs)
 	}
 }
@@ -53,7 +58,7 @@ func TestValidateRequiredFields(t *testing.T) {
 			skill := validSkill()
 			tt.mutate(skill)
 
-			got := messages(NewSkillValidator().Validate(skill), ports.SeverityError)
+			got := messages(NewSkillValidator(testCategories).Validate(skill), ports.SeverityError)
 			for _, m := range got {
 				if strings.Contains(m, tt.wantSub) {
 					return
@@ -72,7 +77,7 @@ func TestValidateNameFormat(t *testing.T) {
 			skill := validSkill()
 			skill.Metadata.Name = name
 
-			if len(messages(NewSkillValidator().Validate(skill), ports.SeverityError)) == 0 {
+			if len(messages(NewSkillValidator(testCategories).Validate(skill), ports.SeverityError)) == 0 {
 				t.Errorf("expected name %q to be rejected", name)
 			}
 		})
@@ -84,7 +89,7 @@ func TestValidateLengthLimits(t *testing.T) {
 		skill := validSkill()
 		skill.Metadata.Description = strings.Repeat("a", MaxDescriptionLength+1)
 
-		if len(messages(NewSkillValidator().Validate(skill), ports.SeverityError)) == 0 {
+		if len(messages(NewSkillValidator(testCategories).Validate(skill), ports.SeverityError)) == 0 {
 			t.Error("expected over-length description to be rejected")
 		}
 	})
@@ -93,7 +98,7 @@ func TestValidateLengthLimits(t *testing.T) {
 		skill := validSkill()
 		skill.Metadata.Name = strings.Repeat("a", MaxNameLength+1)
 
-		if len(messages(NewSkillValidator().Validate(skill), ports.SeverityError)) == 0 {
+		if len(messages(NewSkillValidator(testCategories).Validate(skill), ports.SeverityError)) == 0 {
 			t.Error("expected over-length name to be rejected")
 		}
 	})
@@ -102,7 +107,7 @@ func TestValidateLengthLimits(t *testing.T) {
 		skill := validSkill()
 		skill.Metadata.Description = "Short."
 
-		if len(messages(NewSkillValidator().Validate(skill), ports.SeverityWarning)) == 0 {
+		if len(messages(NewSkillValidator(testCategories).Validate(skill), ports.SeverityWarning)) == 0 {
 			t.Error("expected a warning for a description too short to route on")
 		}
 	})
@@ -112,7 +117,7 @@ func TestValidateWarnsWhenNoBodyExtracted(t *testing.T) {
 	skill := validSkill()
 	skill.Metadata.Overview = ""
 
-	if len(messages(NewSkillValidator().Validate(skill), ports.SeverityWarning)) == 0 {
+	if len(messages(NewSkillValidator(testCategories).Validate(skill), ports.SeverityWarning)) == 0 {
 		t.Error("expected a warning when neither an overview nor topic groups were extracted")
 	}
 }
@@ -124,7 +129,7 @@ func TestValidateAcceptsGroupsWithoutOverview(t *testing.T) {
 	skill.Metadata.Overview = ""
 	skill.Groups = []domain.TopicGroup{{Title: "Architecture", Topics: []domain.Topic{{Title: "Hub and Spoke"}}}}
 
-	if len(NewSkillValidator().Validate(skill)) != 0 {
+	if len(NewSkillValidator(testCategories).Validate(skill)) != 0 {
 		t.Error("topic groups alone should count as an extracted body")
 	}
 }
@@ -133,16 +138,72 @@ func TestValidateWarnsOnMissingSourceURL(t *testing.T) {
 	skill := validSkill()
 	skill.Metadata.SourceURL = ""
 
-	if len(messages(NewSkillValidator().Validate(skill), ports.SeverityWarning)) == 0 {
+	if len(messages(NewSkillValidator(testCategories).Validate(skill), ports.SeverityWarning)) == 0 {
 		t.Error("expected a warning for missing source URL")
 	}
 }
 
+func TestValidateWarnsOnFlaggedMarkup(t *testing.T) {
+	skill := validSkill()
+	skill.LibraryFiles = []domain.LibraryFile{{
+		RelPath:    "index.md",
+		SourcePath: "docs/patterns/index.md",
+		Flagged:    []domain.FlaggedMarkup{{Construct: domain.MarkupMacros, Text: "{{ version }}", Position: domain.Position{Line: 12, Column: 9}}},
+	}}
+
+	errs := NewSkillValidator(testCategories).Validate(skill)
+	if len(errs) != 1 || errs[0].File != "docs/patterns/index.md" || errs[0].Line != 12 || errs[0].Column != 9 || !strings.Contains(errs[0].Message, `(macros) "{{ version }}"`) {
+		t.Errorf("findings = %+v, want one warning on the flagged macro", errs)
+	}
+}
+
+func TestValidateWarnsOnBrokenLinks(t *testing.T) {
+	skill := validSkill()
+	skill.LibraryFiles = []domain.LibraryFile{{
+		RelPath:     "a/index.md",
+		SourcePath:  "docs/patterns/a/index.md",
+		BrokenLinks: []domain.Link{{Dest: "../gone.md", Position: domain.Position{Line: 7, Column: 3}}},
+	}}
+
+	errs := NewSkillValidator(testCategories).Validate(skill)
+	if len(errs) != 1 || errs[0].File != "docs/patterns/a/index.md" || errs[0].Line != 7 || errs[0].Column != 3 || errs[0].Severity != ports.SeverityWarning || !strings.Contains(errs[0].Message, `"../gone.md"`) {
+		t.Errorf("findings = %+v, want one warning on the broken link", errs)
+	}
+}
+
+func TestValidateWarnsOnSkippedAssets(t *testing.T) {
+	skill := validSkill()
+	skill.LibraryFiles = []domain.LibraryFile{{
+		RelPath:    "a/index.md",
+		SourcePath: "docs/patterns/a/index.md",
+		Skipped:    []domain.SkippedAsset{{Path: "patterns/a/bundle.zip", Reason: `extension ".zip" is not among the bundled asset types`, Position: domain.Position{Line: 4, Column: 1}}},
+	}}
+
+	errs := NewSkillValidator(testCategories).Validate(skill)
+	if len(errs) != 1 || errs[0].File != "docs/patterns/a/index.md" || errs[0].Line != 4 || errs[0].Column != 1 || errs[0].Severity != ports.SeverityWarning || !strings.Contains(errs[0].Message, `patterns/a/bundle.zip is not bundled`) {
+		t.Errorf("findings = %+v, want one warning on the skipped asset", errs)
+	}
+}
+
+func TestValidateWarnsOnPagesAtAnIndexURL(t *testing.T) {
+	skill := validSkill()
+	skill.LibraryFiles = []domain.LibraryFile{{
+		RelPath:    "foo.md",
+		SourcePath: "docs/patterns/foo.md",
+		SameURL:    "patterns/foo/index.md",
+	}}
+
+	errs := NewSkillValidator(testCategories).Validate(skill)
+	if len(errs) != 1 || errs[0].File != "docs/patterns/foo.md" || errs[0].Severity != ports.SeverityWarning || !strings.Contains(errs[0].Message, "patterns/foo/index.md") {
+		t.Errorf("findings = %+v, want one warning naming the index page", errs)
+	}
+}
+
 func TestValidateReportsFileContext(t *testing.T) {
 	skill := validSkill()
 	skill.Metadata.Description = ""
 
-	errs := NewSkillValidator().Validate(skill)
+	errs := NewSkillValidator(testCategories).Validate(skill)
 	if len(errs) == 0 {
 		t.Fatal("expected findings")
 	}
@@ -154,5 +215,5 @@ func TestValidateReportsFileContext(t *testing.T) {
 }
 
 func TestSatisfiesPortInterface(t *testing.T) {
-	var _ ports.SkillValidator = NewSkillValidator()
+	var _ ports.SkillValidator = NewSkillValidator(testCategories)
 }